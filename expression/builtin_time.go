@@ -29,6 +29,7 @@ import (
 	"github.com/pingcap/tidb/context"
 	"github.com/pingcap/tidb/mysql"
 	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/terror"
 	"github.com/pingcap/tidb/util/types"
 )
 
@@ -214,8 +215,7 @@ func builtinDateDiff(args []types.Datum, ctx context.Context) (d types.Datum, er
 		return d, nil
 	}
 
-	r := types.DateDiff(t1.Time, t2.Time)
-	d.SetInt64(int64(r))
+	d.SetInt64(types.DateDiff(t1.Time, t2.Time))
 	return d, nil
 }
 
@@ -683,14 +683,15 @@ func builtinDayOfWeek(args []types.Datum, ctx context.Context) (d types.Datum, e
 
 	// No need to check type here.
 	t := d.GetMysqlTime()
-	if t.IsZero() {
+	// Date contains zero month or day has no weekday, MySQL returns NULL too.
+	if t.IsZero() || t.Time.Month() == 0 || t.Time.Day() == 0 {
 		d.SetNull()
 		// TODO: log warning or return error?
 		return d, nil
 	}
 
 	// 1 is Sunday, 2 is Monday, .... 7 is Saturday
-	d.SetInt64(int64(t.Time.Weekday() + 1))
+	d.SetInt64(int64(t.Time.DayOfWeek()))
 	return d, nil
 }
 
@@ -812,7 +813,8 @@ func builtinWeekDay(args []types.Datum, ctx context.Context) (types.Datum, error
 
 	// No need to check type here.
 	t := d.GetMysqlTime()
-	if t.IsZero() {
+	// Date contains zero month or day has no weekday, MySQL returns NULL too.
+	if t.IsZero() || t.Time.Month() == 0 || t.Time.Day() == 0 {
 		// TODO: log warning or return error?
 		d.SetNull()
 		return d, nil
@@ -1124,8 +1126,12 @@ func (b *builtinCurrentDateSig) eval(row []types.Datum) (types.Datum, error) {
 // See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_curdate
 func builtinCurrentDate(args []types.Datum, _ context.Context) (d types.Datum, err error) {
 	year, month, day := time.Now().Date()
+	tm, err := types.FromDate(year, int(month), day, 0, 0, 0, 0)
+	if err != nil {
+		return d, errors.Trace(err)
+	}
 	t := types.Time{
-		Time: types.FromDate(year, int(month), day, 0, 0, 0, 0),
+		Time: tm,
 		Type: mysql.TypeDate, Fsp: 0}
 	d.SetMysqlTime(t)
 	return d, nil
@@ -1405,24 +1411,30 @@ func dateArithFuncFactory(op ast.DateArithType) BuiltinFunc {
 				interval = fmt.Sprintf("%v", ii)
 			}
 		}
-		year, month, day, duration, err := types.ExtractTimeValue(nodeIntervalUnit, interval)
-		if err != nil {
+		iv, err := types.ParseInterval(interval, nodeIntervalUnit)
+		if terror.ErrorEqual(err, types.ErrDatetimeOutOfRange) {
+			return d, nil
+		} else if err != nil {
 			return d, errors.Trace(err)
 		}
 		if op == ast.DateArithSub {
-			year, month, day, duration = -year, -month, -day, -duration
+			iv = types.Interval{Year: -iv.Year, Month: -iv.Month, Day: -iv.Day, Hour: -iv.Hour,
+				Minute: -iv.Minute, Second: -iv.Second, Microsecond: -iv.Microsecond}
 		}
-		// TODO: Consider time_zone variable.
-		t, err := result.Time.GoTime(time.Local)
-		if err != nil {
+		// Add the interval in mysql time to clamp the day like MySQL, e.g. 2016-03-31 minus
+		// 1 MONTH is 2016-02-29 rather than 2016-03-02, and to keep the result independent
+		// of the time zone.
+		t, err := types.AddInterval(result.Time, iv)
+		if terror.ErrorEqual(err, types.ErrDatetimeOutOfRange) || (err == nil && t.Year() < 1) {
+			// MySQL returns NULL when the result is out of range.
+			return d, nil
+		} else if err != nil {
 			return d, errors.Trace(err)
 		}
-		t = t.Add(duration)
-		t = t.AddDate(int(year), int(month), int(day))
-		if t.Nanosecond() == 0 {
+		if t.Microsecond() == 0 {
 			result.Fsp = 0
 		}
-		result.Time = types.FromGoTime(t)
+		result.Time = t
 		d.SetMysqlTime(result)
 		return d, nil
 	}
@@ -64,7 +64,8 @@ func (s *testEvaluatorSuite) TestDate(c *C) {
 	}{
 		{"2000-01-01", 2000, 1, "January", 1, 7, 1, 5, "Saturday", 0, 52, 199952},
 		{"2011-11-11", 2011, 11, "November", 11, 6, 315, 4, "Friday", 45, 45, 201145},
-		{"0000-01-01", int64(0), 1, "January", 1, 7, 1, 5, "Saturday", 1, 52, 1},
+		// Like MySQL, the weekday is calculated from the day number, year 0 is not a leap year.
+		{"0000-01-01", int64(0), 1, "January", 1, 1, 1, 6, "Sunday", 1, 52, 1},
 	}
 
 	dtbl := tblToDtbl(tbl)
@@ -181,6 +182,17 @@ func (s *testEvaluatorSuite) TestDate(c *C) {
 		c.Assert(err, IsNil)
 		c.Assert(v, testutil.DatumEquals, t["YearWeek"][0])
 	}
+
+	// Date contains zero month or day has no weekday.
+	for _, str := range []string{"2016-00-10", "2016-01-00", "2016-00-00"} {
+		args := types.MakeDatums(str)
+		v, err := builtinDayOfWeek(args, s.ctx)
+		c.Assert(err, IsNil)
+		c.Assert(v.IsNull(), IsTrue, Commentf("%s failed.", str))
+		v, err = builtinWeekDay(args, s.ctx)
+		c.Assert(err, IsNil)
+		c.Assert(v.IsNull(), IsTrue, Commentf("%s failed.", str))
+	}
 }
 
 func (s *testEvaluatorSuite) TestDateFormat(c *C) {
@@ -232,6 +244,10 @@ func (s *testEvaluatorSuite) TestClock(c *C) {
 		{"10:10:10.123456", 10, 10, 10, 123456, "10:10:10.123456"},
 		{"11:11:11.11", 11, 11, 11, 110000, "11:11:11.11"},
 		{"2010-10-10 11:11:11.11", 11, 11, 11, 110000, "11:11:11.11"},
+		// The absolute values are returned for negative TIME value.
+		{"-12:30:00", 12, 30, 0, 0, "-12:30:00"},
+		{"-838:59:59", 838, 59, 59, 0, "-838:59:59"},
+		{"-01:02:03.5", 1, 2, 3, 500000, "-01:02:03.5"},
 	}
 
 	dtbl := tblToDtbl(tbl)
@@ -483,6 +499,9 @@ func (s *testEvaluatorSuite) TestDateDiff(c *C) {
 		{"2008-12-31 23:59:59.000001", "2008-12-30 01:01:01.000002", 1},
 		{"1010-11-30 23:59:59", "2010-12-31", -365274},
 		{"1010-11-30", "2210-11-01", -438262},
+		{"2016-01-01 23:59", "2015-12-31 00:00", 1},
+		{"2015-12-31 00:00", "2016-01-01 23:59", -1},
+		{"2016-03-01", "2016-02-28 23:59:59", 2},
 	}
 
 	for _, test := range tests {
@@ -618,6 +637,65 @@ func (s *testEvaluatorSuite) TestDateArithFuncs(c *C) {
 	c.Assert(err, IsNil)
 	c.Assert(v.IsNull(), IsTrue)
 
+	// The day is clamped to the last day of the month.
+	tbl := []struct {
+		op     ast.DateArithType
+		date   string
+		value  interface{}
+		unit   string
+		expect string
+	}{
+		{ast.DateArithSub, "2016-03-31", 1, "MONTH", "2016-02-29"},
+		{ast.DateArithSub, "2015-03-31", 1, "MONTH", "2015-02-28"},
+		{ast.DateArithSub, "2016-02-29", 1, "YEAR", "2015-02-28"},
+		{ast.DateArithSub, "2016-05-31", 1, "QUARTER", "2016-02-29"},
+		{ast.DateArithSub, "2016-03-31", "1-1", "YEAR_MONTH", "2015-02-28"},
+		{ast.DateArithAdd, "2016-01-31", "1-1", "YEAR_MONTH", "2017-02-28"},
+		{ast.DateArithAdd, "2016-01-31 23:00:00", "1 1", "DAY_HOUR", "2016-02-02 00:00:00"},
+		{ast.DateArithAdd, "2016-01-31", 1, "MONTH", "2016-02-29"},
+		{ast.DateArithAdd, "2016-03-31", -1, "MONTH", "2016-02-29"},
+		{ast.DateArithAdd, "2016-01-31 12:30:00", 1, "MONTH", "2016-02-29 12:30:00"},
+		{ast.DateArithSub, "0001-01-02", 1, "DAY", "0001-01-01"},
+		{ast.DateArithSub, "0001-01-01 00:00:01", 1, "SECOND", "0001-01-01 00:00:00"},
+		{ast.DateArithAdd, "9999-12-31 23:59:58", 1, "SECOND", "9999-12-31 23:59:59"},
+		// The result doesn't depend on the daylight saving time of any time zone.
+		{ast.DateArithAdd, "2016-03-13 01:30:00", 1, "HOUR", "2016-03-13 02:30:00"},
+		{ast.DateArithAdd, "2016-03-27 01:30:00", "1:00", "HOUR_MINUTE", "2016-03-27 02:30:00"},
+	}
+	for i, t := range tbl {
+		args = types.MakeDatums(t.date, t.value, t.unit)
+		v, err = dateArithFuncFactory(t.op)(args, s.ctx)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(v.GetMysqlTime().String(), Equals, t.expect, Commentf("%d failed.", i))
+	}
+
+	// The result out of range is NULL like MySQL.
+	nullTbl := []struct {
+		op    ast.DateArithType
+		date  string
+		value interface{}
+		unit  string
+	}{
+		{ast.DateArithAdd, "9999-12-31", 1, "DAY"},
+		{ast.DateArithAdd, "9999-12-31 23:59:59", 1, "SECOND"},
+		{ast.DateArithAdd, "9999-12-01", 1, "MONTH"},
+		{ast.DateArithAdd, "9999-01-01", 1, "YEAR"},
+		{ast.DateArithSub, "0000-01-01", 1, "MONTH"},
+		{ast.DateArithSub, "0001-01-01", 2, "YEAR"},
+		{ast.DateArithSub, "0001-01-01", 1, "DAY"},
+		{ast.DateArithAdd, "0001-01-01", -1, "DAY"},
+		{ast.DateArithSub, "0001-01-01 00:00:00", 1, "SECOND"},
+		{ast.DateArithSub, "0001-01-31", 1, "MONTH"},
+		{ast.DateArithAdd, "2016-01-01", int64(7905747460161236407), "WEEK"},
+		{ast.DateArithAdd, "2016-01-01", "9223372036854775807", "HOUR"},
+	}
+	for i, t := range nullTbl {
+		args = types.MakeDatums(t.date, t.value, t.unit)
+		v, err = dateArithFuncFactory(t.op)(args, s.ctx)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(v.IsNull(), IsTrue, Commentf("%d failed.", i))
+	}
+
 	args = types.MakeDatums(date[1], nil, "DAY")
 	v, err = dateSub(args, s.ctx)
 	c.Assert(err, IsNil)
@@ -267,7 +267,7 @@ func (s *testEvaluatorSuite) TestBinopNumeric(c *C) {
 		{1, ast.Mul, types.NewDecFromInt(1), 1},
 		{uint64(1), ast.Mul, 1, 1},
 		{uint64(1), ast.Mul, uint64(1), 1},
-		{types.Time{Time: types.FromDate(0, 0, 0, 0, 0, 0, 0)}, ast.Mul, 0, 0},
+		{types.Time{Time: types.ZeroTime}, ast.Mul, 0, 0},
 		{types.ZeroDuration, ast.Mul, 0, 0},
 		{types.Time{Time: types.FromGoTime(time.Now()), Fsp: 0, Type: mysql.TypeDatetime}, ast.Mul, 0, 0},
 		{types.Time{Time: types.FromGoTime(time.Now()), Fsp: 6, Type: mysql.TypeDatetime}, ast.Mul, 0, 0},
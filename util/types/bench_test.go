@@ -0,0 +1,73 @@
+// Copyright 2016 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"testing"
+	gotime "time"
+)
+
+func BenchmarkWeekday(b *testing.B) {
+	t := newMysqlTime(2016, 12, 31, 23, 59, 59, 999999)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		t.Weekday()
+	}
+}
+
+func BenchmarkYearDayAndWeek(b *testing.B) {
+	rows := make([]mysqlTime, 0, 10000)
+	for daynr := calcDaynr(1990, 1, 1); len(rows) < cap(rows); daynr++ {
+		rows = append(rows, FromDays(int64(daynr)))
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, t := range rows {
+			t.YearDay()
+			t.Week(0)
+			t.YearWeek(3)
+		}
+	}
+}
+
+func BenchmarkGoTimeBatch(b *testing.B) {
+	rows := make([]mysqlTime, 0, 10000)
+	for daynr := calcDaynr(1990, 1, 1); len(rows) < cap(rows); daynr++ {
+		rows = append(rows, FromDays(int64(daynr)))
+	}
+	out := make([]gotime.Time, len(rows))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		GoTimeBatch(rows, gotime.UTC, out)
+	}
+}
+
+func BenchmarkGoTime(b *testing.B) {
+	t := newMysqlTime(2016, 12, 31, 23, 59, 59, 999999)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		t.GoTime(gotime.UTC)
+	}
+}
+
+func BenchmarkGoTimeLocal(b *testing.B) {
+	t := newMysqlTime(2016, 12, 31, 23, 59, 59, 999999)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		t.GoTime(gotime.Local)
+	}
+}
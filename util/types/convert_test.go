@@ -243,6 +243,12 @@ func (s *testTypeConvertSuite) TestConvertType(c *C) {
 	c.Assert(v, Equals, int64(2015))
 	v, err = Convert(ZeroDuration, ft)
 	c.Assert(v, Equals, int64(time.Now().Year()))
+	// The current year is read from nowFunc.
+	defer func(f func() time.Time) { nowFunc = f }(nowFunc)
+	nowFunc = func() time.Time { return time.Date(2008, 8, 8, 0, 0, 0, 0, time.Local) }
+	v, err = Convert(ZeroDuration, ft)
+	c.Assert(err, IsNil)
+	c.Assert(v, Equals, int64(2008))
 
 	// For enum
 	ft = NewFieldType(mysql.TypeEnum)
@@ -1015,7 +1015,7 @@ func (d *Datum) convertToMysqlYear(sc *variable.StatementContext, target *FieldT
 	case KindMysqlTime:
 		y = int64(d.GetMysqlTime().Time.Year())
 	case KindMysqlDuration:
-		y = int64(time.Now().Year())
+		y = int64(nowFunc().Year())
 	default:
 		ret, err = d.convertToInt(sc, NewFieldType(mysql.TypeLonglong))
 		if err != nil {
@@ -29,6 +29,12 @@ var (
 	ErrDivByZero = terror.ClassTypes.New(codeDivByZero, "Division by 0")
 	// ErrBadNumber is return when parsing an invalid binary decimal number.
 	ErrBadNumber = terror.ClassTypes.New(codeBadNumber, "Bad Number")
+	// ErrDatetimeOutOfRange is returned when a datetime field is out of its range, like hour 24.
+	ErrDatetimeOutOfRange = terror.ClassTypes.New(codeDatetimeOutOfRange, "Datetime Out Of Range")
+	// ErrZeroDateNotAllowed is returned when a date with zero month or day can't be used.
+	ErrZeroDateNotAllowed = terror.ClassTypes.New(codeZeroDateNotAllowed, "Zero Date Not Allowed")
+	// ErrTruncatedWrongValue is returned when a value can't be converted to the target type.
+	ErrTruncatedWrongValue = terror.ClassTypes.New(codeTruncatedWrongValue, "Truncated Wrong Value")
 )
 
 const (
@@ -38,6 +44,10 @@ const (
 	codeTruncated   terror.ErrCode = terror.ErrCode(mysql.WarnDataTruncated)
 	codeOverflow    terror.ErrCode = terror.ErrCode(mysql.ErrWarnDataOutOfRange)
 	codeDivByZero   terror.ErrCode = terror.ErrCode(mysql.ErrDivisionByZero)
+
+	codeDatetimeOutOfRange  terror.ErrCode = terror.ErrCode(mysql.ErrDatetimeFunctionOverflow)
+	codeZeroDateNotAllowed  terror.ErrCode = terror.ErrCode(mysql.ErrWrongValue)
+	codeTruncatedWrongValue terror.ErrCode = terror.ErrCode(mysql.ErrTruncatedWrongValue)
 )
 
 func init() {
@@ -46,6 +56,10 @@ func init() {
 		codeTruncated:   mysql.WarnDataTruncated,
 		codeOverflow:    mysql.ErrWarnDataOutOfRange,
 		codeDivByZero:   mysql.ErrDivisionByZero,
+
+		codeDatetimeOutOfRange:  mysql.ErrDatetimeFunctionOverflow,
+		codeZeroDateNotAllowed:  mysql.ErrWrongValue,
+		codeTruncatedWrongValue: mysql.ErrTruncatedWrongValue,
 	}
 	terror.ErrClassToMySQLCodes[terror.ClassTypes] = typesMySQLErrCodes
 }
@@ -55,10 +55,10 @@ func (s *testTimeSuite) TestTimeFormatMethod(c *C) {
 			// For invalid date month or year = 0, MySQL behavior is confusing, %U (which format Week()) is 52, but Week() is 0.
 			// It's because in MySQL, Week() checks invalid date before processing, but DateFormat() don't.
 			// So there are some difference to MySQL here (%U %u %V %v), TiDB user should not rely on those corner case behavior.
-			// %W %w %a is not compatible in this case because Week() use GoTime() currently.
+			// %W %a are empty for dates containing zero month or day.
 			"0000-01-00 00:00:00.123456",
 			`%b %M %m %c %D %d %e %j %k %h %i %p %r %T %s %f %U %u %V %v %a %W %w %X %x %Y %y %%`,
-			`Jan January 01 1 0th 00 0 000 0 12 00 AM 12:00:00 AM 00:00:00 00 123456 00 00 00 52 Sun Sunday 0 4294967295 4294967295 0000 00 %`,
+			`Jan January 01 1 0th 00 0 000 0 12 00 AM 12:00:00 AM 00:00:00 00 123456 00 00 00 52   6 4294967295 4294967295 0000 00 %`,
 		},
 	}
 	for i, t := range tblDate {
@@ -72,31 +72,112 @@ func (s *testTimeSuite) TestTimeFormatMethod(c *C) {
 	}
 }
 
+func (s *testTimeSuite) TestDateFormatWithNames(c *C) {
+	deDE := &TimeNames{
+		MonthNames: []string{
+			"Januar", "Februar", "März", "April", "Mai", "Juni",
+			"Juli", "August", "September", "Oktober", "November", "Dezember",
+		},
+		AbbrevMonthNames: []string{
+			"Jan", "Feb", "Mär", "Apr", "Mai", "Jun",
+			"Jul", "Aug", "Sep", "Okt", "Nov", "Dez",
+		},
+		WeekdayNames:       []string{"Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag", "Sonntag"},
+		AbbrevWeekdayNames: []string{"Mo", "Di", "Mi", "Do", "Fr", "Sa", "So"},
+	}
+
+	tbl := []struct {
+		Input  string
+		Expect string
+		EnUS   string
+	}{
+		{"2010-01-07 23:12:34", "Januar Jan Donnerstag Do 4", "January Jan Thursday Thu 4"},
+		{"2016-03-13 00:00:00", "März Mär Sonntag So 0", "March Mar Sunday Sun 0"},
+		{"2016-12-31 00:00:00", "Dezember Dez Samstag Sa 6", "December Dec Saturday Sat 6"},
+	}
+	for i, t := range tbl {
+		tm, err := ParseTime(t.Input, mysql.TypeDatetime, 0)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		str, err := tm.DateFormatWithNames("%M %b %W %a %w", deDE)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(str, Equals, t.Expect, Commentf("%d failed.", i))
+
+		// DateFormat uses en_US names by default.
+		str, err = tm.DateFormat("%M %b %W %a %w")
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(str, Equals, t.EnUS, Commentf("%d failed.", i))
+		str, err = tm.DateFormatWithNames("%M %b %W %a %w", EnUSTimeNames)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(str, Equals, t.EnUS, Commentf("%d failed.", i))
+	}
+}
+
+func (s *testTimeSuite) TestGetFormat(c *C) {
+	tbl := []struct {
+		kind     string
+		standard string
+		expect   string
+	}{
+		{"DATE", "USA", "%m.%d.%Y"},
+		{"DATE", "JIS", "%Y-%m-%d"},
+		{"DATE", "ISO", "%Y-%m-%d"},
+		{"DATE", "EUR", "%d.%m.%Y"},
+		{"DATE", "INTERNAL", "%Y%m%d"},
+		{"DATETIME", "USA", "%Y-%m-%d %H.%i.%s"},
+		{"DATETIME", "JIS", "%Y-%m-%d %H:%i:%s"},
+		{"DATETIME", "ISO", "%Y-%m-%d %H:%i:%s"},
+		{"DATETIME", "EUR", "%Y-%m-%d %H.%i.%s"},
+		{"DATETIME", "INTERNAL", "%Y%m%d%H%i%s"},
+		{"TIMESTAMP", "USA", "%Y-%m-%d %H.%i.%s"},
+		{"TIMESTAMP", "JIS", "%Y-%m-%d %H:%i:%s"},
+		{"TIMESTAMP", "ISO", "%Y-%m-%d %H:%i:%s"},
+		{"TIMESTAMP", "EUR", "%Y-%m-%d %H.%i.%s"},
+		{"TIMESTAMP", "INTERNAL", "%Y%m%d%H%i%s"},
+		{"TIME", "USA", "%h:%i:%s %p"},
+		{"TIME", "JIS", "%H:%i:%s"},
+		{"TIME", "ISO", "%H:%i:%s"},
+		{"TIME", "EUR", "%H.%i.%s"},
+		{"TIME", "INTERNAL", "%H%i%s"},
+		{"date", "usa", "%m.%d.%Y"},
+	}
+
+	for i, t := range tbl {
+		format, err := GetFormat(t.kind, t.standard)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(format, Equals, t.expect, Commentf("%d failed.", i))
+	}
+
+	_, err := GetFormat("YEAR", "USA")
+	c.Assert(err, NotNil)
+	_, err = GetFormat("DATE", "GB")
+	c.Assert(err, NotNil)
+}
+
 func (s *testTimeSuite) TestStrToDate(c *C) {
 	testcases := []struct {
 		input  string
 		format string
 		expect TimeInternal
 	}{
-		{`01,05,2013`, `%d,%m,%Y`, FromDate(2013, 5, 1, 0, 0, 0, 0)},
-		{`May 01, 2013`, `%M %d,%Y`, FromDate(2013, 5, 1, 0, 0, 0, 0)},
-		{`a09:30:17`, `a%h:%i:%s`, FromDate(0, 0, 0, 9, 30, 17, 0)},
-		{`09:30:17a`, `%h:%i:%s`, FromDate(0, 0, 0, 9, 30, 17, 0)},
+		{`01,05,2013`, `%d,%m,%Y`, newMysqlTime(2013, 5, 1, 0, 0, 0, 0)},
+		{`May 01, 2013`, `%M %d,%Y`, newMysqlTime(2013, 5, 1, 0, 0, 0, 0)},
+		{`a09:30:17`, `a%h:%i:%s`, newMysqlTime(0, 0, 0, 9, 30, 17, 0)},
+		{`09:30:17a`, `%h:%i:%s`, newMysqlTime(0, 0, 0, 9, 30, 17, 0)},
 		{`abc`, `abc`, ZeroTime},
-		{`09`, `%m`, FromDate(0, 9, 0, 0, 0, 0, 0)},
-		{`09`, `%s`, FromDate(0, 0, 0, 0, 0, 9, 0)},
-		{`12:43:24 AM`, `%r`, FromDate(0, 0, 0, 12, 43, 24, 0)},
-		{`11:43:24 PM`, `%r`, FromDate(0, 0, 0, 23, 43, 24, 0)},
-		{`00:12:13`, `%T`, FromDate(0, 0, 0, 0, 12, 13, 0)},
-		{`23:59:59`, `%T`, FromDate(0, 0, 0, 23, 59, 59, 0)},
+		{`09`, `%m`, newMysqlTime(0, 9, 0, 0, 0, 0, 0)},
+		{`09`, `%s`, newMysqlTime(0, 0, 0, 0, 0, 9, 0)},
+		{`12:43:24 AM`, `%r`, newMysqlTime(0, 0, 0, 12, 43, 24, 0)},
+		{`11:43:24 PM`, `%r`, newMysqlTime(0, 0, 0, 23, 43, 24, 0)},
+		{`00:12:13`, `%T`, newMysqlTime(0, 0, 0, 0, 12, 13, 0)},
+		{`23:59:59`, `%T`, newMysqlTime(0, 0, 0, 23, 59, 59, 0)},
 		{`00/00/0000`, `%m/%d/%Y`, ZeroTime},
-		{`04/30/2004`, `%m/%d/%Y`, FromDate(2004, 4, 30, 0, 0, 0, 0)},
-		{`15:35:00`, `%H:%i:%s`, FromDate(0, 0, 0, 15, 35, 0, 0)},
-		{`Jul 17 33`, `%b %k %S`, FromDate(0, 7, 0, 17, 0, 33, 0)},
-		{`2016-January:7 432101`, `%Y-%M:%l %f`, FromDate(2016, 1, 0, 7, 0, 0, 432101)},
-		{`10:13 PM`, `%l:%i %p`, FromDate(0, 0, 0, 22, 13, 0, 0)},
-		{`12:00:00 AM`, `%h:%i:%s %p`, FromDate(0, 0, 0, 0, 0, 0, 0)},
-		{`12:00:00 PM`, `%h:%i:%s %p`, FromDate(0, 0, 0, 12, 0, 0, 0)},
+		{`04/30/2004`, `%m/%d/%Y`, newMysqlTime(2004, 4, 30, 0, 0, 0, 0)},
+		{`15:35:00`, `%H:%i:%s`, newMysqlTime(0, 0, 0, 15, 35, 0, 0)},
+		{`Jul 17 33`, `%b %k %S`, newMysqlTime(0, 7, 0, 17, 0, 33, 0)},
+		{`2016-January:7 432101`, `%Y-%M:%l %f`, newMysqlTime(2016, 1, 0, 7, 0, 0, 432101)},
+		{`10:13 PM`, `%l:%i %p`, newMysqlTime(0, 0, 0, 22, 13, 0, 0)},
+		{`12:00:00 AM`, `%h:%i:%s %p`, newMysqlTime(0, 0, 0, 0, 0, 0, 0)},
+		{`12:00:00 PM`, `%h:%i:%s %p`, newMysqlTime(0, 0, 0, 12, 0, 0, 0)},
 	}
 	for i, test := range testcases {
 		var t Time
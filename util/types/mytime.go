@@ -14,19 +14,30 @@
 package types
 
 import (
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"strings"
 	gotime "time"
 
 	"github.com/juju/errors"
+	"github.com/pingcap/tidb/terror"
 )
 
 type mysqlTime struct {
 	year        uint16 // year <= 9999
 	month       uint8  // month <= 12
 	day         uint8  // day <= 31
-	hour        uint8  // hour <= 23
+	hour        uint32 // hour <= 23 for datetime, hour <= 838 for TIME value
 	minute      uint8  // minute <= 59
 	second      uint8  // second <= 59
 	microsecond uint32
+	neg         bool // neg is only used by TIME value
+	// fsp is the number of fractional digits String prints, so trailing zeros like 10:00:00.00
+	// are kept. It doesn't take part in comparison. For a value wrapped in Time, Time.Fsp is
+	// authoritative and this field only affects the String of the bare TimeInternal.
+	fsp int8
 }
 
 func (t mysqlTime) Year() int {
@@ -57,21 +68,198 @@ func (t mysqlTime) Microsecond() int {
 	return int(t.microsecond)
 }
 
-func (t mysqlTime) Weekday() gotime.Weekday {
-	// TODO: Consider time_zone variable.
-	t1, err := t.GoTime(gotime.Local)
+// IsNegative returns whether t is a negative TIME value.
+func (t mysqlTime) IsNegative() bool {
+	return t.neg
+}
+
+// IsZero returns whether all the fields of t are zero, i.e. 0000-00-00 00:00:00.
+func (t mysqlTime) IsZero() bool {
+	t.fsp = 0
+	return t == mysqlTime{}
+}
+
+// IsZeroDate returns whether the date part of t is 0000-00-00, regardless of the time part.
+func (t mysqlTime) IsZeroDate() bool {
+	return t.year == 0 && t.month == 0 && t.day == 0
+}
+
+// Equal returns whether t and other are the same time value, all the fields including microsecond are compared.
+func (t mysqlTime) Equal(other TimeInternal) bool {
+	return compareTime(t, other) == 0
+}
+
+// EqualIgnoreFsp is like Equal but ignores the microsecond, it's used when comparing
+// a fsp 0 value with a value carrying fractional seconds.
+func (t mysqlTime) EqualIgnoreFsp(other TimeInternal) bool {
+	return t.neg == other.IsNegative() && datetimeToUint64(t) == datetimeToUint64(other)
+}
+
+// Kind guesses the kind of t from its fields, a TIME value has no date part or is negative,
+// and a DATE value has no time part. The all-zero value is ambiguous and is treated as DATETIME,
+// callers knowing the column type should use Time.Kind instead.
+func (t mysqlTime) Kind() TimeKind {
+	hasTime := t.hour != 0 || t.minute != 0 || t.second != 0 || t.microsecond != 0
+	switch {
+	case t.neg || (isTimeOnly(t) && hasTime):
+		return TimeKindTime
+	case !isTimeOnly(t) && !hasTime:
+		return TimeKindDate
+	}
+	return TimeKindDatetime
+}
+
+// CompareToInt compares t with n in numeric context like MySQL, t is converted to the integer
+// in YYYYMMDDHHMMSS format by datetimeToUint64, e.g. 2016-01-01 00:00:00 is 20160101000000,
+// so it's greater than 20160101. The microsecond isn't part of the integer form, so values only
+// different in microsecond compare equal, e.g. 2016-01-01 00:00:00.5 equals 20160101000000.
+// A TIME value is in HHMMSS format and keeps its sign.
+func (t mysqlTime) CompareToInt(n int64) int {
+	v := int64(datetimeToUint64(t))
+	if t.neg {
+		v = -v
+	}
+	return CompareInt64(v, n)
+}
+
+// Before returns whether t is before other, ordered the same way as Compare.
+func (t mysqlTime) Before(other TimeInternal) bool {
+	return compareTime(t, other) < 0
+}
+
+// After returns whether t is after other, ordered the same way as Compare.
+func (t mysqlTime) After(other TimeInternal) bool {
+	return compareTime(t, other) > 0
+}
+
+// Validate checks t against the NO_ZERO_DATE and NO_ZERO_IN_DATE SQL modes.
+// If noZeroDate is set, date 0000-00-00 is rejected. If noZeroInDate is set, dates
+// with zero month or day like 2016-00-10 or 2016-10-00 are rejected, but 0000-00-00
+// is still controlled by noZeroDate, same as MySQL.
+func (t mysqlTime) Validate(noZeroDate, noZeroInDate bool) error {
+	if t.IsZeroDate() {
+		if noZeroDate {
+			return errors.Trace(ErrInvalidTimeFormat)
+		}
+		return nil
+	}
+	if noZeroInDate && (t.month == 0 || t.day == 0) {
+		return errors.Trace(ErrInvalidTimeFormat)
+	}
+	return nil
+}
+
+// ValidateTimeOfDay checks the time part of t as the time of day of a datetime,
+// hour must be in [0, 23] and minute and second in [0, 59]. A TIME value is a duration
+// which allows hour up to 838 and a sign, it's checked against MinTime and MaxTime instead.
+func (t mysqlTime) ValidateTimeOfDay() error {
+	if t.neg || !IsValidTimeOfDay(int(t.hour), int(t.minute), int(t.second), int(t.microsecond)) {
+		return errors.Trace(ErrInvalidTimeFormat)
+	}
+	return nil
+}
+
+// Quarter returns the quarter of the year, in range [1, 4], or 0 for zero month.
+func (t mysqlTime) Quarter() int {
+	return (int(t.month) + 2) / 3
+}
+
+// QuarterStart returns the first day of the quarter of t at midnight, i.e. January, April, July
+// or October 1 of the year. It returns ZeroTime for zero month which has no quarter.
+func (t mysqlTime) QuarterStart() mysqlTime {
+	if t.month == 0 {
+		return ZeroTime
+	}
+	// TruncTo rejects zero day, but the day is truncated anyway.
+	res, err := t.withDay(1).TruncTo(intervalQUARTER)
 	if err != nil {
+		return ZeroTime
+	}
+	return res
+}
+
+// Weekday returns the day of the week of t.
+// It's calculated from the day number directly, so the weekday of a wall clock date
+// doesn't depend on any time zone, and dates containing zero month or day are supported too.
+func (t mysqlTime) Weekday() gotime.Weekday {
+	daynr := calcDaynr(int(t.year), int(t.month), int(t.day))
+	// calcWeekday returns 0 for Monday, but gotime.Weekday is 0 for Sunday.
+	return gotime.Weekday((calcWeekday(daynr, false) + 1) % 7)
+}
+
+// DayOfWeek returns the weekday index like MySQL DAYOFWEEK, 1 for Sunday, 2 for Monday ... 7 for Saturday.
+// It returns 0 for date contains zero month or day.
+func (t mysqlTime) DayOfWeek() int {
+	if t.month == 0 || t.day == 0 {
+		return 0
+	}
+	daynr := calcDaynr(int(t.year), int(t.month), int(t.day))
+	return calcWeekday(daynr, true) + 1
+}
+
+// WeekdayName returns the en_US name of the weekday of t like MySQL DAYNAME, e.g. Saturday,
+// or an empty string for date contains zero month or day. It's the same as DATE_FORMAT %W.
+func (t mysqlTime) WeekdayName() string {
+	// EnUSTimeNames is always valid.
+	name, _ := t.WeekdayNameWithNames(EnUSTimeNames)
+	return name
+}
+
+// WeekdayNameWithNames is like WeekdayName, but the name is taken from names like
+// DateFormatWithNames. An error is returned if names doesn't have 7 weekday names.
+func (t mysqlTime) WeekdayNameWithNames(names *TimeNames) (string, error) {
+	if names == nil || len(names.WeekdayNames) != 7 {
+		return "", errors.Errorf("invalid weekday names %v", names)
+	}
+	if t.month == 0 || t.day == 0 {
+		return "", nil
+	}
+	daynr := calcDaynr(int(t.year), int(t.month), int(t.day))
+	return names.WeekdayNames[calcWeekday(daynr, false)], nil
+}
+
+// MonthName returns the en_US name of the month of t like MySQL MONTHNAME, e.g. December,
+// or an empty string for zero month. It's the same as DATE_FORMAT %M.
+func (t mysqlTime) MonthName() string {
+	// EnUSTimeNames is always valid.
+	name, _ := t.MonthNameWithNames(EnUSTimeNames)
+	return name
+}
+
+// MonthNameWithNames is like MonthName, but the name is taken from names like
+// DateFormatWithNames. An error is returned if names doesn't have 12 month names.
+func (t mysqlTime) MonthNameWithNames(names *TimeNames) (string, error) {
+	if names == nil || len(names.MonthNames) != 12 {
+		return "", errors.Errorf("invalid month names %v", names)
+	}
+	if t.month == 0 || t.month > 12 {
+		return "", nil
+	}
+	return names.MonthNames[t.month-1], nil
+}
+
+// DaysInMonth returns the number of days in the month of t, or 0 for zero month.
+func (t mysqlTime) DaysInMonth() int {
+	if t.month == 0 || t.month > 12 {
 		return 0
 	}
-	return t1.Weekday()
+	return lastDayOfMonth(int(t.year), int(t.month))
 }
 
 func (t mysqlTime) YearDay() int {
 	if t.month == 0 || t.day == 0 {
 		return 0
 	}
-	return calcDaynr(int(t.year), int(t.month), int(t.day)) -
-		calcDaynr(int(t.year), 1, 1) + 1
+	return newDayInfo(int(t.year), int(t.month), int(t.day)).yearDay
+}
+
+// DayOfYear is like YearDay, but returns an error for date contains zero month or day,
+// for callers which need to raise a warning instead of returning 0.
+func (t mysqlTime) DayOfYear() (int, error) {
+	if t.month == 0 || t.day == 0 {
+		return 0, errors.Trace(ErrInvalidTimeFormat)
+	}
+	return t.YearDay(), nil
 }
 
 func (t mysqlTime) YearWeek(mode int) (int, int) {
@@ -79,6 +267,13 @@ func (t mysqlTime) YearWeek(mode int) (int, int) {
 	return calcWeek(&t, behavior)
 }
 
+// YearWeekInt returns the YEARWEEK result in YYYYWW format. The year is the one the week
+// belongs to, which may differ from the calendar year for dates near January 1.
+func (t mysqlTime) YearWeekInt(mode int) int {
+	year, week := t.YearWeek(mode)
+	return year*100 + week
+}
+
 func (t mysqlTime) Week(mode int) int {
 	if t.month == 0 || t.day == 0 {
 		return 0
@@ -87,47 +282,1264 @@ func (t mysqlTime) Week(mode int) int {
 	return week
 }
 
+// WeekOfYear returns the ISO 8601 week number in range [1, 53] like MySQL WEEKOFYEAR, it's the same as Week(3).
+func (t mysqlTime) WeekOfYear() int {
+	return t.Week(3)
+}
+
+// GoTime converts t to gotime.Time in loc. The values gotime.Time can't represent exactly are
+// rejected with ErrZeroDateNotAllowed, ErrDatetimeOutOfRange or ErrTruncatedWrongValue.
 func (t mysqlTime) GoTime(loc *gotime.Location) (gotime.Time, error) {
+	// TIME value may be negative or exceed 23 hours, it's not a valid wall clock time.
+	if t.neg {
+		return gotime.Time{}, errors.Trace(ErrTruncatedWrongValue)
+	}
+	if t.hour > 23 {
+		return gotime.Time{}, errors.Trace(ErrDatetimeOutOfRange)
+	}
 	// gotime.Time can't represent month 0 or day 0, date contains 0 would be converted to a nearest date,
-	// For example, 2006-12-00 00:00:00 would become 2015-11-30 23:59:59.
-	tm := gotime.Date(t.Year(), gotime.Month(t.Month()), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Microsecond()*1000, loc)
-	year, month, day := tm.Date()
-	hour, minute, second := tm.Clock()
-	microsec := tm.Nanosecond() / 1000
+	// For example, 2006-12-00 00:00:00 would become 2006-11-30 00:00:00.
+	tm := t.GoTimeClamped(loc)
+	if loc == gotime.UTC {
+		// Every valid wall clock time exists exactly once in UTC, so checking the fields is
+		// enough, and it's cheaper than comparing them with the fields decomposed from tm.
+		if t.month == 0 || t.day == 0 || t.fieldsOutOfRange() {
+			return tm, errors.Trace(t.goTimeError())
+		}
+		return tm, nil
+	}
 	// This function will check the result, and return an error if it's not the same with the origin input.
-	if year != t.Year() || int(month) != t.Month() || day != t.Day() ||
-		hour != t.Hour() || minute != t.Minute() || second != t.Second() ||
-		microsec != t.Microsecond() {
-		return tm, errors.Trace(ErrInvalidTimeFormat)
+	if !t.sameAsGoTime(tm) {
+		return tm, errors.Trace(t.goTimeError())
 	}
 	return tm, nil
 }
 
+// goTimeError returns the most specific error for t which GoTime rejects: ErrZeroDateNotAllowed
+// for zero month or day, ErrDatetimeOutOfRange for fields out of range, and ErrTruncatedWrongValue
+// for the others, like a wall clock time skipped by the daylight saving time of the location.
+func (t mysqlTime) goTimeError() error {
+	switch {
+	case t.month == 0 || t.day == 0:
+		return ErrZeroDateNotAllowed
+	case t.year > 9999 || t.fieldsOutOfRange():
+		return ErrDatetimeOutOfRange
+	}
+	return ErrTruncatedWrongValue
+}
+
+// fieldsOutOfRange returns whether any field of t would be normalized by gotime.Date,
+// month and day should be nonzero.
+func (t mysqlTime) fieldsOutOfRange() bool {
+	if t.month > 12 {
+		return true
+	}
+	lastDay := lastDayOfMonth(int(t.year), int(t.month))
+	// gotime uses the proleptic Gregorian calendar in which year 0 is a leap year, unlike MySQL,
+	// so 0000-02-29 is not normalized and the round-trip check in GoTime accepts it too.
+	if t.year == 0 && t.month == 2 {
+		lastDay = 29
+	}
+	return int(t.day) > lastDay || t.hour > 23 || t.minute > 59 || t.second > 59 || t.microsecond > 999999
+}
+
+// sameAsGoTime returns whether tm has exactly the fields of t, i.e. t is a valid wall clock time
+// and is not normalized by gotime.Date.
+func (t mysqlTime) sameAsGoTime(tm gotime.Time) bool {
+	if t.neg {
+		return false
+	}
+	year, month, day := tm.Date()
+	hour, minute, second := tm.Clock()
+	return year == t.Year() && int(month) == t.Month() && day == t.Day() &&
+		hour == t.Hour() && minute == t.Minute() && second == t.Second() &&
+		tm.Nanosecond()/1000 == t.Microsecond()
+}
+
+// GoTimeClamped is like GoTime but never returns an error, it's for lenient callers like logging.
+// Fields out of range are normalized by gotime.Date, e.g. 2006-12-00 becomes 2006-11-30 and
+// 2006-00-15 becomes 2005-12-15. The sign of a negative TIME value is ignored.
+func (t mysqlTime) GoTimeClamped(loc *gotime.Location) gotime.Time {
+	return gotime.Date(t.Year(), gotime.Month(t.Month()), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Microsecond()*1000, loc)
+}
+
+// GoTimeBatch converts ts to gotime.Time into out, which must be at least as long as ts.
+// Every element is converted like GoTimeClamped, and the index of the first element which
+// GoTime would reject is returned with the same error GoTime returns for it, or -1 if all of
+// them are valid. Only one error is created for a batch, so it's cheaper than calling GoTime
+// for each element.
+func GoTimeBatch(ts []mysqlTime, loc *gotime.Location, out []gotime.Time) (firstErrIdx int, err error) {
+	if len(out) < len(ts) {
+		return -1, errors.Errorf("output length %d is less than input length %d", len(out), len(ts))
+	}
+	firstErrIdx = -1
+	for i, t := range ts {
+		out[i] = t.GoTimeClamped(loc)
+		if firstErrIdx < 0 && !t.sameAsGoTime(out[i]) {
+			firstErrIdx = i
+		}
+	}
+	if firstErrIdx >= 0 {
+		_, err = ts[firstErrIdx].GoTime(loc)
+		return firstErrIdx, errors.Trace(err)
+	}
+	return -1, nil
+}
+
+// Format returns a textual representation of the time value formatted
+// according to layout, which uses the MySQL DATE_FORMAT specifiers.
+// See http://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_date-format
+func (t mysqlTime) Format(layout string) (string, error) {
+	return formatTime(t, layout)
+}
+
+// String implements the fmt.Stringer interface, it renders t as YYYY-MM-DD HH:MM:SS[.ffffff]
+// like MySQL. The fractional part has fsp digits if fsp is set, otherwise it's omitted if
+// microsecond is zero. A negative TIME value is rendered as 0000-00-00 -HH:MM:SS.
+func (t mysqlTime) String() string {
+	layout := "%Y-%m-%d %H:%i:%s"
+	if t.fsp > 0 || t.microsecond > 0 {
+		layout += ".%f"
+	}
+	// We control the layout, so no error would occur.
+	str, _ := t.Format(layout)
+	if t.fsp > 0 {
+		// Keep fsp digits of the 6 digits printed by %f.
+		str = str[:len(str)-MaxFsp+int(t.fsp)]
+	}
+	return str
+}
+
+const (
+	intervalYEAR        = "YEAR"
+	intervalQUARTER     = "QUARTER"
+	intervalMONTH       = "MONTH"
+	intervalWEEK        = "WEEK"
+	intervalDAY         = "DAY"
+	intervalHOUR        = "HOUR"
+	intervalMINUTE      = "MINUTE"
+	intervalSECOND      = "SECOND"
+	intervalMICROSECOND = "MICROSECOND"
+)
+
+// AddInterval adds amount units to t, it implements MySQL DATE_ADD and DATE_SUB.
+// Adding months, quarters or years clamps the day to the last day of the resulting month,
+// e.g. 2016-01-31 + 1 MONTH is 2016-02-29. Other units carry across all the fields
+// by day number calculation. ErrDatetimeOutOfRange is returned if the result is out of
+// the range 0001-01-01 to 9999-12-31, for which MySQL returns NULL rather than an error,
+// and ErrInvalidTimeFormat is returned for t containing zero month or day.
+func (t mysqlTime) AddInterval(unit string, amount int) (mysqlTime, error) {
+	if t.month == 0 || t.day == 0 {
+		return t, errors.Trace(ErrInvalidTimeFormat)
+	}
+
+	n := int64(amount)
+	switch strings.ToUpper(unit) {
+	case intervalYEAR:
+		months, err := mulInterval(n, 12, maxIntervalMonths)
+		if err != nil {
+			return t, errors.Trace(err)
+		}
+		return t.addMonths(months)
+	case intervalQUARTER:
+		months, err := mulInterval(n, 3, maxIntervalMonths)
+		if err != nil {
+			return t, errors.Trace(err)
+		}
+		return t.addMonths(months)
+	case intervalMONTH:
+		return t.addMonths(n)
+	case intervalWEEK:
+		days, err := mulInterval(n, 7, maxDaynr)
+		if err != nil {
+			return t, errors.Trace(err)
+		}
+		return t.addDateTime(days, 0, 0)
+	case intervalDAY:
+		return t.addDateTime(n, 0, 0)
+	case intervalHOUR:
+		return t.addDateTime(n/24, n%24*3600, 0)
+	case intervalMINUTE:
+		return t.addDateTime(n/(24*60), n%(24*60)*60, 0)
+	case intervalSECOND:
+		return t.addDateTime(n/secondsIn24Hour, n%secondsIn24Hour, 0)
+	case intervalMICROSECOND:
+		return t.addDateTime(0, n/1e6, n%1e6)
+	}
+	return t, errors.Errorf("invalid interval unit %s", unit)
+}
+
+// TruncTo returns the start of the unit period t is in by zeroing all the finer fields,
+// e.g. 2016-03-15 10:20:30 truncated to MONTH is 2016-03-01 00:00:00, and QUARTER snaps
+// to the first month of the quarter. The supported units are YEAR, QUARTER, MONTH, DAY,
+// HOUR, MINUTE and SECOND.
+func (t mysqlTime) TruncTo(unit string) (mysqlTime, error) {
+	if t.month == 0 || t.day == 0 {
+		return t, errors.Trace(ErrInvalidTimeFormat)
+	}
+
+	t.microsecond = 0
+	switch strings.ToUpper(unit) {
+	case intervalYEAR:
+		return t.withMonth(1).withDay(1).withHour(0).withMinute(0).withSecond(0), nil
+	case intervalQUARTER:
+		return t.withMonth((t.Quarter()-1)*3 + 1).withDay(1).withHour(0).withMinute(0).withSecond(0), nil
+	case intervalMONTH:
+		return t.withDay(1).withHour(0).withMinute(0).withSecond(0), nil
+	case intervalDAY:
+		return t.withHour(0).withMinute(0).withSecond(0), nil
+	case intervalHOUR:
+		return t.withMinute(0).withSecond(0), nil
+	case intervalMINUTE:
+		return t.withSecond(0), nil
+	case intervalSECOND:
+		return t, nil
+	}
+	return t, errors.Errorf("invalid interval unit %s", unit)
+}
+
+// AddIntervalFields adds iv returned by ParseInterval to t, it applies the compound units like
+// YEAR_MONTH and DAY_SECOND which AddInterval can't. The years and months are added first with the
+// day clamped like AddInterval, then the days and the time parts are added with carries, so
+// 2016-01-30 plus 1 month and 1 day is 2016-03-01 rather than 2016-02-29.
+func (t mysqlTime) AddIntervalFields(iv Interval) (mysqlTime, error) {
+	if t.month == 0 || t.day == 0 {
+		return t, errors.Trace(ErrInvalidTimeFormat)
+	}
+	// Each part is range checked first, so the sums below never overflow.
+	var p [7]int64
+	for i, part := range []struct{ n, factor, limit int64 }{
+		{iv.Year, 12, maxIntervalMonths},
+		{iv.Month, 1, maxIntervalMonths},
+		{iv.Day, 1, maxDaynr},
+		{iv.Hour, 3600, maxIntervalSeconds},
+		{iv.Minute, 60, maxIntervalSeconds},
+		{iv.Second, 1, maxIntervalSeconds},
+		{iv.Microsecond, 1, maxIntervalSeconds * 1e6},
+	} {
+		var err error
+		if p[i], err = mulInterval(part.n, part.factor, part.limit); err != nil {
+			return t, errors.Trace(err)
+		}
+	}
+
+	if months := p[0] + p[1]; months != 0 {
+		var err error
+		if t, err = t.addMonths(months); err != nil {
+			return t, errors.Trace(err)
+		}
+	}
+	return t.addDateTime(p[2], p[3]+p[4]+p[5], p[6])
+}
+
+// AddGoDuration adds d to t, the nanoseconds beyond microsecond precision are truncated.
+// The carries are calculated with the day number rather than time.Time, so the result has
+// the same range check as AddInterval.
+func (t mysqlTime) AddGoDuration(d gotime.Duration) (mysqlTime, error) {
+	if t.month == 0 || t.day == 0 {
+		return t, errors.Trace(ErrInvalidTimeFormat)
+	}
+	microseconds := int64(d / gotime.Microsecond)
+	return t.addDateTime(0, microseconds/1e6, microseconds%1e6)
+}
+
+// SubInterval subtracts amount of unit from t, it implements MySQL DATE_SUB by negating
+// the amount passed to AddInterval. The day is clamped for YEAR, QUARTER and MONTH units
+// in the same way, e.g. 2016-03-31 minus 1 MONTH is 2016-02-29.
+func (t mysqlTime) SubInterval(unit string, amount int) (mysqlTime, error) {
+	if amount != 0 && amount == -amount {
+		// The negation of the minimum int overflows, and it's out of range in any unit.
+		return t, errors.Trace(ErrDatetimeOutOfRange)
+	}
+	return t.AddInterval(unit, -amount)
+}
+
+// AddMonths adds months to the date t like MySQL DATE_ADD with MONTH unit,
+// the day is clamped to the last day of the resulting month.
+func AddMonths(t TimeInternal, months int64) (TimeInternal, error) {
+	if t.Month() == 0 || t.Day() == 0 {
+		return t, errors.Trace(ErrInvalidTimeFormat)
+	}
+	mt, err := checkedMysqlTime(t)
+	if err != nil {
+		return t, errors.Trace(err)
+	}
+	res, err := mt.addMonths(months)
+	if err != nil {
+		return t, errors.Trace(err)
+	}
+	return res, nil
+}
+
+// AddInterval adds iv to the date t like MySQL DATE_ADD, see AddIntervalFields for the details.
+func AddInterval(t TimeInternal, iv Interval) (TimeInternal, error) {
+	mt, err := checkedMysqlTime(t)
+	if err != nil {
+		return t, errors.Trace(err)
+	}
+	res, err := mt.AddIntervalFields(iv)
+	if err != nil {
+		return t, errors.Trace(err)
+	}
+	return res, nil
+}
+
+// DateRange returns the dates from start to end inclusively, stepping stepN units by AddInterval,
+// e.g. the MONTH series from 2016-01-31 to 2016-04-30 is 01-31, 02-29, 03-31, 04-30. Each date is
+// calculated from start rather than the previous date, so a clamped day doesn't drift the series.
+// stepN must be positive for start before end and negative for start after end, otherwise
+// the walk would never reach end and an error is returned. An error is returned too if the range
+// has more than maxDateRangeLen dates, e.g. the MICROSECOND series over years.
+func DateRange(start, end TimeInternal, stepUnit string, stepN int) ([]mysqlTime, error) {
+	from, err := checkedMysqlTime(start)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cmp := Compare(start, end)
+	if cmp == 0 {
+		return []mysqlTime{from}, nil
+	}
+	if stepN == 0 || (cmp < 0) != (stepN > 0) {
+		return nil, errors.Errorf("step %d %s never reaches %v from %v", stepN, stepUnit, end, start)
+	}
+
+	dates := []mysqlTime{from}
+	for i := 1; ; i++ {
+		t, err := from.AddInterval(stepUnit, i*stepN)
+		if terror.ErrorEqual(err, ErrDatetimeOutOfRange) {
+			// The walk is out of the supported range, which must be beyond end.
+			break
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if Compare(t, end)*cmp < 0 {
+			break
+		}
+		if len(dates) == maxDateRangeLen {
+			return nil, errors.Errorf("more than %d dates from %v to %v by %d %s", maxDateRangeLen, start, end, stepN, stepUnit)
+		}
+		dates = append(dates, t)
+	}
+	return dates, nil
+}
+
+// mulInterval returns n * factor, ErrDatetimeOutOfRange is returned if the absolute value of
+// the product is larger than limit, which makes sure the multiplication never overflows.
+func mulInterval(n, factor, limit int64) (int64, error) {
+	if n > limit/factor || n < -limit/factor {
+		return 0, errors.Trace(ErrDatetimeOutOfRange)
+	}
+	return n * factor, nil
+}
+
+// addMonths adds months to t, the day is clamped to the last day of the resulting month.
+func (t mysqlTime) addMonths(months int64) (mysqlTime, error) {
+	period := int64(t.year)*12 + int64(t.month) - 1 + months
+	if period < 0 || period >= 10000*12 {
+		return t, errors.Trace(ErrDatetimeOutOfRange)
+	}
+	t.year = uint16(period / 12)
+	t.month = uint8(period%12 + 1)
+	if lastDay := lastDayOfMonth(int(t.year), int(t.month)); int(t.day) > lastDay {
+		t.day = uint8(lastDay)
+	}
+	return t, nil
+}
+
+// addDateTime adds days, seconds and microseconds to t, carries are normalized across all the fields.
+func (t mysqlTime) addDateTime(days, seconds, microseconds int64) (mysqlTime, error) {
+	microseconds += int64(t.microsecond)
+	seconds += int64(t.hour)*3600 + int64(t.minute)*60 + int64(t.second) + microseconds/1e6
+	microseconds %= 1e6
+	if microseconds < 0 {
+		microseconds += 1e6
+		seconds--
+	}
+	days += seconds / secondsIn24Hour
+	seconds %= secondsIn24Hour
+	if seconds < 0 {
+		seconds += secondsIn24Hour
+		days--
+	}
+
+	if days > maxDaynr || days < -maxDaynr {
+		return t, errors.Trace(ErrDatetimeOutOfRange)
+	}
+	daynr := int64(calcDaynr(int(t.year), int(t.month), int(t.day))) + days
+	if daynr < minDaynr || daynr > maxDaynr {
+		return t, errors.Trace(ErrDatetimeOutOfRange)
+	}
+	year, month, day := getDateFromDaynr(int(daynr))
+	return newMysqlTime(year, month, day, int(seconds/3600), int(seconds%3600/60), int(seconds%60), int(microseconds)), nil
+}
+
+// RoundToFsp rounds the microsecond of t to fsp digits, e.g. 2016-12-31 23:59:59.999999 rounds
+// to 2017-01-01 00:00:00 with fsp 0. The carry is propagated through all the fields, and an
+// ErrDatetimeOutOfRange is returned if the result is out of range.
+func (t mysqlTime) RoundToFsp(fsp int) (mysqlTime, error) {
+	fsp, err := checkFsp(fsp)
+	if err != nil {
+		return t, errors.Trace(err)
+	}
+
+	base := uint32(math.Pow10(MaxFsp - fsp))
+	microsecond := (t.microsecond + base/2) / base * base
+	if microsecond < 1e6 {
+		t.microsecond = microsecond
+		t.fsp = int8(fsp)
+		return t, nil
+	}
+
+	if isTimeOnly(t) {
+		seconds := int(t.hour)*3600 + int(t.minute)*60 + int(t.second) + 1
+		if seconds > maxTimeSeconds {
+			return t, errors.Trace(ErrDatetimeOutOfRange)
+		}
+		neg := t.neg
+		calcTimeFromSec(&t, seconds, 0)
+		t.neg = neg
+		t.fsp = int8(fsp)
+		return t, nil
+	}
+	if t.month == 0 || t.day == 0 {
+		return t, errors.Trace(ErrInvalidTimeFormat)
+	}
+	t, err = t.addDateTime(0, 0, int64(1e6-t.microsecond))
+	t.fsp = int8(fsp)
+	return t, errors.Trace(err)
+}
+
+// ConvertTo converts t for storing in a column of kind with fsp, following the rules of MySQL:
+// the time part is dropped without rounding for DATE, with an ErrTruncated warning if it isn't zero,
+// and the microsecond is rounded to fsp for DATETIME and TIME, e.g. 2016-12-31 23:59:59.5 is
+// 2016-12-31 as DATE but 2017-01-01 00:00:00 as DATETIME(0). A TIME value t is converted to
+// DATE or DATETIME on the current date by CurDate in the local time zone, and like MySQL
+// CAST(TIME AS DATE) there is no warning for dropping its time part.
+func (t mysqlTime) ConvertTo(kind TimeKind, fsp int) (mysqlTime, []error, error) {
+	if kind != TimeKindTime && t.Kind() == TimeKindTime {
+		seconds := int64(t.hour)*3600 + int64(t.minute)*60 + int64(t.second)
+		microseconds := int64(t.microsecond)
+		if t.neg {
+			seconds, microseconds = -seconds, -microseconds
+		}
+		res, err := CurDate(gotime.Local).addDateTime(0, seconds, microseconds)
+		if err != nil {
+			return ZeroTime, nil, errors.Trace(err)
+		}
+		if kind == TimeKindDate {
+			return res.DatePart(), nil, nil
+		}
+		t = res
+	}
+
+	switch kind {
+	case TimeKindDate:
+		var warnings []error
+		if t.hour != 0 || t.minute != 0 || t.second != 0 || t.microsecond != 0 {
+			warnings = append(warnings, errors.Trace(ErrTruncated))
+		}
+		return t.DatePart(), warnings, nil
+	case TimeKindTime:
+		if !isTimeOnly(t) {
+			t = t.TimePart()
+		}
+	}
+	res, err := t.RoundToFsp(fsp)
+	if err != nil {
+		return ZeroTime, nil, errors.Trace(err)
+	}
+	return res, nil, nil
+}
+
+// TruncateToFsp zeroes the microsecond digits of t beyond fsp without carrying.
+// Invalid fsp is treated as DefaultFsp.
+func (t mysqlTime) TruncateToFsp(fsp int) mysqlTime {
+	fsp, err := checkFsp(fsp)
+	if err != nil {
+		fsp = DefaultFsp
+	}
+	base := uint32(math.Pow10(MaxFsp - fsp))
+	t.microsecond = t.microsecond / base * base
+	t.fsp = int8(fsp)
+	return t
+}
+
+// mysqlTimeBinaryLen is the length of the binary encoding of mysqlTime.
+//
+//    1 byte  sign (0 for negative, 1 for others)
+//    2 bytes year
+//    1 byte  month
+//    1 byte  day
+//    2 bytes hour
+//    1 byte  minute
+//    1 byte  second
+//    3 bytes microsecond
+//    1 byte  fsp
+//
+// All the fields are big endian, and the bytes after sign except fsp are inverted for negative
+// value, so the encoded bytes are in the same order with Compare, fsp only breaks the ties.
+// The encoding of the first version has no fsp byte, it's still decoded with fsp 0.
+const (
+	mysqlTimeBinaryLen   = 13
+	mysqlTimeBinaryLenV1 = 12
+)
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (t mysqlTime) MarshalBinary() ([]byte, error) {
+	if t.hour > 0xFFFF {
+		return nil, errors.Trace(ErrInvalidTimeFormat)
+	}
+	data := make([]byte, mysqlTimeBinaryLen)
+	binary.BigEndian.PutUint16(data[1:], t.year)
+	data[3] = t.month
+	data[4] = t.day
+	binary.BigEndian.PutUint16(data[5:], uint16(t.hour))
+	data[7] = t.minute
+	data[8] = t.second
+	data[9] = byte(t.microsecond >> 16)
+	data[10] = byte(t.microsecond >> 8)
+	data[11] = byte(t.microsecond)
+	if t.neg {
+		for i := 1; i < mysqlTimeBinaryLenV1; i++ {
+			data[i] = ^data[i]
+		}
+	} else {
+		data[0] = 1
+	}
+	data[12] = byte(t.fsp)
+	return data, nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (t *mysqlTime) UnmarshalBinary(data []byte) error {
+	if (len(data) != mysqlTimeBinaryLen && len(data) != mysqlTimeBinaryLenV1) || data[0] > 1 {
+		return errors.Trace(ErrInvalidTimeFormat)
+	}
+	var fsp int8
+	if len(data) == mysqlTimeBinaryLen {
+		if int(data[12]) > MaxFsp {
+			return errors.Trace(ErrInvalidTimeFormat)
+		}
+		fsp = int8(data[12])
+	}
+	buf := make([]byte, mysqlTimeBinaryLenV1)
+	copy(buf, data)
+	neg := buf[0] == 0
+	if neg {
+		for i := 1; i < len(buf); i++ {
+			buf[i] = ^buf[i]
+		}
+	}
+	v := mysqlTime{
+		year:        binary.BigEndian.Uint16(buf[1:]),
+		month:       buf[3],
+		day:         buf[4],
+		hour:        uint32(binary.BigEndian.Uint16(buf[5:])),
+		minute:      buf[7],
+		second:      buf[8],
+		microsecond: uint32(buf[9])<<16 | uint32(buf[10])<<8 | uint32(buf[11]),
+		neg:         neg,
+		fsp:         fsp,
+	}
+	if err := v.checkStoredFields(); err != nil {
+		return errors.Trace(err)
+	}
+	*t = v
+	return nil
+}
+
+// checkStoredFields checks the fields of t decoded from the storage, which may be corrupted,
+// ErrInvalidTimeFormat is returned unless t is a valid DATETIME, DATE or TIME value.
+// Zero month or day is allowed like NewMysqlTimeChecked.
+func (t mysqlTime) checkStoredFields() error {
+	if t.year > 9999 || checkDateFields(int(t.year), int(t.month), int(t.day)) != nil {
+		return ErrInvalidTimeFormat
+	}
+	if t.minute > 59 || t.second > 59 || t.microsecond > 999999 {
+		return ErrInvalidTimeFormat
+	}
+	if isTimeOnly(t) {
+		if int(t.hour)*3600+int(t.minute)*60+int(t.second) > maxTimeSeconds {
+			return ErrInvalidTimeFormat
+		}
+	} else if t.hour > 23 || t.neg {
+		return ErrInvalidTimeFormat
+	}
+	return nil
+}
+
+// PackDatetimeV2 packs t into int64 in the layout of the MySQL DATETIME2 format, from the high bits:
+//
+//    1 bit  sign (always 0 for datetime)
+//    17 bits year*13+month
+//    5 bits  day
+//    5 bits  hour
+//    6 bits  minute
+//    6 bits  second
+//    24 bits microsecond
+//
+// It's the same as TIME_to_longlong_datetime_packed of MySQL, whose on-disk integer part is
+// the 40 bits above microsecond plus 0x8000000000. The packed values are in the same order as
+// Compare, and so are their big endian bytes. t must be a valid datetime, a TIME value or a
+// datetime with hour out of [0, 23] doesn't fit in the layout.
+func (t mysqlTime) PackDatetimeV2() int64 {
+	ymd := (int64(t.year)*13+int64(t.month))<<5 | int64(t.day)
+	hms := int64(t.hour)<<12 | int64(t.minute)<<6 | int64(t.second)
+	return (ymd<<17|hms)<<24 | int64(t.microsecond)
+}
+
+// UnpackDatetimeV2 unpacks v packed by PackDatetimeV2, ErrInvalidTimeFormat is returned if any
+// field is out of range.
+func UnpackDatetimeV2(v int64) (mysqlTime, error) {
+	if v < 0 {
+		return ZeroTime, errors.Trace(ErrInvalidTimeFormat)
+	}
+	microsecond := v % (1 << 24)
+	v >>= 24
+	ymd, hms := v>>17, v%(1<<17)
+	ym := ymd >> 5
+	t, err := NewMysqlTimeChecked(int(ym/13), int(ym%13), int(ymd%(1<<5)),
+		int(hms>>12), int((hms>>6)%(1<<6)), int(hms%(1<<6)), int(microsecond))
+	return t, errors.Trace(err)
+}
+
+// GobEncode implements the gob.GobEncoder interface, it uses the same encoding as MarshalBinary.
+func (t mysqlTime) GobEncode() ([]byte, error) {
+	data, err := t.MarshalBinary()
+	return data, errors.Trace(err)
+}
+
+// GobDecode implements the gob.GobDecoder interface.
+func (t *mysqlTime) GobDecode(data []byte) error {
+	return errors.Trace(t.UnmarshalBinary(data))
+}
+
+// Value implements the driver.Valuer interface, the value is the string form of t.
+// Negative TIME value is rejected, since Scan can't parse it back as a datetime.
+func (t mysqlTime) Value() (driver.Value, error) {
+	if t.neg {
+		return nil, errors.Trace(ErrInvalidTimeFormat)
+	}
+	return t.String(), nil
+}
+
+// Scan implements the sql.Scanner interface, src can be a []byte or string literal
+// like "2016-12-31 23:59:59.999999" or "0000-00-00", or a time.Time. SQL NULL is rejected
+// like time.Time does, so it's not mistaken for the zero date, use a nullable wrapper for
+// the nullable columns instead.
+func (t *mysqlTime) Scan(src interface{}) error {
+	switch x := src.(type) {
+	case nil:
+		return errors.New("cannot scan NULL into datetime")
+	case []byte:
+		return t.scanString(string(x))
+	case string:
+		return t.scanString(x)
+	case gotime.Time:
+		*t = FromGoTime(x).(mysqlTime)
+		return nil
+	}
+	return errors.Errorf("cannot scan %T into datetime", src)
+}
+
+func (t *mysqlTime) scanString(str string) error {
+	mt, err := ParseDatetimeLiteral(str)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	*t = mt
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface, the value is the String form of t.
+// Every value MySQL stores is encoded, including zero date "0000-00-00 00:00:00", zero in date
+// like "2016-00-00 00:00:00" and TIME value over 24 hours, only corrupted fields are rejected.
+func (t mysqlTime) MarshalJSON() ([]byte, error) {
+	if err := t.checkStoredFields(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return json.Marshal(t.String())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (t *mysqlTime) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return errors.Trace(err)
+	}
+	if s == zeroDatetimeStr {
+		*t = ZeroTime
+		return nil
+	}
+	v, err := parseDatetime(s, MaxFsp)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	tmp, ok := v.Time.(mysqlTime)
+	if !ok {
+		return errors.Trace(ErrInvalidTimeFormat)
+	}
+	if _, err = tmp.GoTime(gotime.UTC); err != nil {
+		return errors.Trace(err)
+	}
+	*t = tmp
+	return nil
+}
+
+// newMysqlTime builds a mysqlTime without any validation.
+// Note that out-of-range fields wrap silently, e.g. day 256 becomes 0, so it should only
+// be used by trusted callers which have checked the fields, others should use NewMysqlTimeChecked.
+// Building with the debug tag panics instead, see fieldsOverflow.
 func newMysqlTime(year, month, day, hour, minute, second, microsecond int) mysqlTime {
+	if debugCheckFields && fieldsOverflow(year, month, day, hour, minute, second, microsecond) {
+		panic(errors.Errorf("mysqlTime field overflow: %d-%d-%d %d:%d:%d.%d", year, month, day, hour, minute, second, microsecond))
+	}
 	return mysqlTime{
-		uint16(year),
-		uint8(month),
-		uint8(day),
-		uint8(hour),
-		uint8(minute),
-		uint8(second),
-		uint32(microsecond),
+		year:        uint16(year),
+		month:       uint8(month),
+		day:         uint8(day),
+		hour:        uint32(hour),
+		minute:      uint8(minute),
+		second:      uint8(second),
+		microsecond: uint32(microsecond),
+	}
+}
+
+// fieldsOverflow returns whether any field doesn't fit in its storage type in mysqlTime,
+// newMysqlTime would wrap such field silently, e.g. day 256 becomes 0.
+func fieldsOverflow(year, month, day, hour, minute, second, microsecond int) bool {
+	return year < 0 || year > math.MaxUint16 ||
+		month < 0 || month > math.MaxUint8 ||
+		day < 0 || day > math.MaxUint8 ||
+		hour < 0 || int64(hour) > math.MaxUint32 ||
+		minute < 0 || minute > math.MaxUint8 ||
+		second < 0 || second > math.MaxUint8 ||
+		microsecond < 0 || int64(microsecond) > math.MaxUint32
+}
+
+// NewMysqlTimeChecked is like newMysqlTime but checks every field before building the datetime,
+// ErrInvalidTimeFormat is returned for out-of-range field. Zero month or day is allowed.
+func NewMysqlTimeChecked(year, month, day, hour, minute, second, microsecond int) (mysqlTime, error) {
+	if year < 0 || year > 9999 || month < 0 || day < 0 {
+		return ZeroTime, errors.Trace(ErrInvalidTimeFormat)
+	}
+	if err := checkDateFields(year, month, day); err != nil {
+		return ZeroTime, errors.Trace(err)
+	}
+	if hour < 0 || hour > 23 || minute < 0 || minute > 59 || second < 0 || second > 59 {
+		return ZeroTime, errors.Trace(ErrInvalidTimeFormat)
+	}
+	if microsecond < 0 || microsecond > 999999 {
+		return ZeroTime, errors.Trace(ErrInvalidTimeFormat)
+	}
+	return newMysqlTime(year, month, day, hour, minute, second, microsecond), nil
+}
+
+// checkedMysqlTime converts t to mysqlTime by NewMysqlTimeChecked, so the fields of a TimeInternal
+// are validated rather than wrapped silently by newMysqlTime.
+func checkedMysqlTime(t TimeInternal) (mysqlTime, error) {
+	mt, err := NewMysqlTimeChecked(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Microsecond())
+	return mt, errors.Trace(err)
+}
+
+// DatePart returns a copy of t with the time part zeroed, it backs MySQL DATE().
+func (t mysqlTime) DatePart() mysqlTime {
+	return newMysqlTime(int(t.year), int(t.month), int(t.day), 0, 0, 0, 0)
+}
+
+// TimePart returns a copy of t with the date part zeroed, it backs MySQL TIME().
+// The sign of a negative TIME value is kept.
+func (t mysqlTime) TimePart() mysqlTime {
+	t.year, t.month, t.day = 0, 0, 0
+	return t
+}
+
+// withYear returns a copy of t with the year set, t itself is unchanged.
+func (t mysqlTime) withYear(year int) mysqlTime {
+	t.year = uint16(year)
+	return t
+}
+
+// withMonth returns a copy of t with the month set, t itself is unchanged.
+func (t mysqlTime) withMonth(month int) mysqlTime {
+	t.month = uint8(month)
+	return t
+}
+
+// withDay returns a copy of t with the day set, t itself is unchanged.
+func (t mysqlTime) withDay(day int) mysqlTime {
+	t.day = uint8(day)
+	return t
+}
+
+// withHour returns a copy of t with the hour set, t itself is unchanged.
+func (t mysqlTime) withHour(hour int) mysqlTime {
+	t.hour = uint32(hour)
+	return t
+}
+
+// withMinute returns a copy of t with the minute set, t itself is unchanged.
+func (t mysqlTime) withMinute(minute int) mysqlTime {
+	t.minute = uint8(minute)
+	return t
+}
+
+// withSecond returns a copy of t with the second set, t itself is unchanged.
+func (t mysqlTime) withSecond(second int) mysqlTime {
+	t.second = uint8(second)
+	return t
+}
+
+// withFsp returns a copy of t with the fsp set, t itself is unchanged.
+// The fsp out of [0, MaxFsp] is clamped, because String keeps fsp digits of the fraction.
+func (t mysqlTime) withFsp(fsp int) mysqlTime {
+	if fsp < 0 {
+		fsp = 0
+	} else if fsp > MaxFsp {
+		fsp = MaxFsp
 	}
+	t.fsp = int8(fsp)
+	return t
 }
 
+// withMicrosecond returns a copy of t with the microsecond set, t itself is unchanged.
+func (t mysqlTime) withMicrosecond(microsecond int) mysqlTime {
+	t.microsecond = uint32(microsecond)
+	return t
+}
+
+// calcTimeFromSec sets the time part of to from seconds and microseconds, the hour may exceed 23.
+// The microseconds are carried into the seconds first, so they may have different signs,
+// and a negative total makes a negative TIME value.
 func calcTimeFromSec(to *mysqlTime, seconds, microseconds int) {
-	to.hour = uint8(seconds / 3600)
-	seconds = seconds % 3600
-	to.minute = uint8(seconds / 60)
-	to.second = uint8(seconds % 60)
-	to.microsecond = uint32(microseconds)
+	total := int64(seconds)*1e6 + int64(microseconds)
+	to.neg = total < 0
+	if to.neg {
+		total = -total
+	}
+	to.microsecond = uint32(total % 1e6)
+	total /= 1e6
+	to.hour = uint32(total / 3600)
+	to.minute = uint8(total % 3600 / 60)
+	to.second = uint8(total % 60)
+}
+
+// maxTimeSeconds is the number of seconds of the maximum TIME value 838:59:59.
+const maxTimeSeconds = 838*3600 + 59*60 + 59
+
+// SecToTime converts seconds and microseconds to a TIME value, it implements MySQL SEC_TO_TIME.
+// The result is clipped to the TIME range [-838:59:59, 838:59:59].
+func SecToTime(seconds int, microseconds int) mysqlTime {
+	// Clip the seconds far out of range before carrying the microseconds into them,
+	// so the total microseconds can't overflow.
+	secs := clipInt64(int64(seconds), 1<<40)
+	secs = clipInt64(secs+int64(microseconds/1e6), maxTimeSeconds+1)
+	total := clipInt64(secs*1e6+int64(microseconds%1e6), maxTimeSeconds*1e6)
+
+	var t mysqlTime
+	calcTimeFromSec(&t, int(total/1e6), int(total%1e6))
+	return t
+}
+
+// clipInt64 clips n to the range [-limit, limit].
+func clipInt64(n, limit int64) int64 {
+	if n > limit {
+		return limit
+	} else if n < -limit {
+		return -limit
+	}
+	return n
+}
+
+// nowFunc returns the current time, all the functions reading the clock in this package call it,
+// so tests can replace it with a fixed clock. It's not protected by any lock, production code
+// must not reassign it, and tests reassigning it must not run concurrently with its users.
+var nowFunc = gotime.Now
+
+// Now returns the current datetime in loc rounded to fsp, it backs MySQL NOW().
+// An invalid fsp is treated as DefaultFsp.
+func Now(loc *gotime.Location, fsp int) mysqlTime {
+	tm := nowFunc().In(loc)
+	t := newMysqlTime(tm.Year(), int(tm.Month()), tm.Day(), tm.Hour(), tm.Minute(), tm.Second(), tm.Nanosecond()/1000)
+	fsp, err := checkFsp(fsp)
+	if err != nil {
+		fsp = DefaultFsp
+	}
+	rounded, err := t.RoundToFsp(fsp)
+	if err != nil {
+		// Rounding 9999-12-31 23:59:59.5 up overflows, truncate it instead.
+		return t.TruncateToFsp(fsp)
+	}
+	return rounded
+}
+
+// CurDate returns the current date in loc, it backs MySQL CURDATE().
+// The date is never rounded up to the next day.
+func CurDate(loc *gotime.Location) mysqlTime {
+	return Now(loc, MaxFsp).DatePart()
+}
+
+// CurTime returns the current time of day in loc rounded to fsp, it backs MySQL CURTIME().
+func CurTime(loc *gotime.Location, fsp int) mysqlTime {
+	return Now(loc, fsp).TimePart()
+}
+
+// TimeToSec returns the number of seconds of the time part of t, it implements MySQL TIME_TO_SEC.
+func TimeToSec(t TimeInternal) int64 {
+	seconds := int64(t.Hour())*3600 + int64(t.Minute())*60 + int64(t.Second())
+	if t.IsNegative() {
+		return -seconds
+	}
+	return seconds
+}
+
+// GoDuration converts the TIME value t to gotime.Duration, the microsecond is kept.
+// ErrInvalidTimeFormat is returned if t has a date part or is out of the TIME range.
+func (t mysqlTime) GoDuration() (gotime.Duration, error) {
+	if !isTimeOnly(t) {
+		return 0, errors.Trace(ErrInvalidTimeFormat)
+	}
+	seconds := int64(t.hour)*3600 + int64(t.minute)*60 + int64(t.second)
+	if seconds > maxTimeSeconds || (seconds == maxTimeSeconds && t.microsecond > 0) {
+		return 0, errors.Trace(ErrInvalidTimeFormat)
+	}
+	d := gotime.Duration(seconds)*gotime.Second + gotime.Duration(t.microsecond)*gotime.Microsecond
+	if t.neg {
+		return -d, nil
+	}
+	return d, nil
+}
+
+// TimeDiff returns t1 - t2 as a TIME value, it implements MySQL TIMEDIFF.
+// Both arguments should be TIME values or both DATETIME values, the result is clipped to the TIME range.
+func TimeDiff(t1, t2 TimeInternal) (mysqlTime, error) {
+	if isTimeOnly(t1) != isTimeOnly(t2) {
+		return ZeroTime, errors.Trace(ErrInvalidTimeFormat)
+	}
+	seconds, microseconds, neg := calcTimeDiff(t1, t2, 1)
+	if neg {
+		seconds, microseconds = -seconds, -microseconds
+	}
+	return SecToTime(seconds, microseconds), nil
+}
+
+// AddTime adds the TIME value d to t, it implements MySQL ADDTIME.
+// If t is a DATETIME value, the carry of the time part goes into the date part,
+// otherwise the result is a TIME value clipped to the TIME range.
+func AddTime(t, d TimeInternal) (mysqlTime, error) {
+	return addTime(t, d, 1)
+}
+
+// SubTime subtracts the TIME value d from t, it implements MySQL SUBTIME.
+func SubTime(t, d TimeInternal) (mysqlTime, error) {
+	return addTime(t, d, -1)
+}
+
+func addTime(t, d TimeInternal, sign int64) (mysqlTime, error) {
+	if !isTimeOnly(d) {
+		return ZeroTime, errors.Trace(ErrInvalidTimeFormat)
+	}
+	seconds, microseconds := sign*TimeToSec(d), sign*int64(d.Microsecond())
+	if d.IsNegative() {
+		microseconds = -microseconds
+	}
+
+	if isTimeOnly(t) {
+		microseconds += TimeToSec(t)*1e6 + seconds*1e6
+		if t.IsNegative() {
+			microseconds -= int64(t.Microsecond())
+		} else {
+			microseconds += int64(t.Microsecond())
+		}
+		return SecToTime(int(microseconds/1e6), int(microseconds%1e6)), nil
+	}
+
+	if t.Month() == 0 || t.Day() == 0 {
+		return ZeroTime, errors.Trace(ErrInvalidTimeFormat)
+	}
+	mt := newMysqlTime(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Microsecond())
+	return mt.addDateTime(0, seconds, microseconds)
+}
+
+// UnixTimestamp returns the seconds and microseconds since 1970-01-01 00:00:00 UTC,
+// t is interpreted in the location loc, it implements MySQL UNIX_TIMESTAMP.
+// An error is returned for zero date and the time before 1970-01-01 00:00:00 UTC.
+func UnixTimestamp(t TimeInternal, loc *gotime.Location) (int64, int, error) {
+	if compareTime(t, ZeroTime) == 0 {
+		return 0, 0, errors.Trace(ErrInvalidTimeFormat)
+	}
+	tm, err := t.GoTime(loc)
+	if err != nil {
+		return 0, 0, errors.Trace(err)
+	}
+	seconds := tm.Unix()
+	if seconds < 0 {
+		return 0, 0, errors.Trace(ErrInvalidTimeFormat)
+	}
+	return seconds, t.Microsecond(), nil
+}
+
+// FromUnixTime converts the seconds and microseconds since 1970-01-01 00:00:00 UTC to
+// the time in the location loc, it implements MySQL FROM_UNIXTIME.
+// An error is returned for the negative timestamp, and ErrDatetimeOutOfRange is returned
+// if the time in loc is after 9999-12-31 23:59:59.999999.
+func FromUnixTime(sec int64, microsec int64, loc *gotime.Location) (mysqlTime, error) {
+	if sec < 0 || microsec < 0 || microsec >= 1e6 {
+		return ZeroTime, errors.Trace(ErrInvalidTimeFormat)
+	}
+	// Reject the seconds far out of range first, so gotime doesn't overflow.
+	if sec > maxUnixSeconds+secondsIn24Hour {
+		return ZeroTime, errors.Trace(ErrDatetimeOutOfRange)
+	}
+	tm := gotime.Unix(sec, microsec*1000).In(loc)
+	year, month, day := tm.Date()
+	hour, minute, second := tm.Clock()
+	if year > 9999 {
+		return ZeroTime, errors.Trace(ErrDatetimeOutOfRange)
+	}
+	return newMysqlTime(year, int(month), day, hour, minute, second, tm.Nanosecond()/1000), nil
+}
+
+// ConvertTZ converts t from the location from to the location to, it implements MySQL CONVERT_TZ.
+// Invalid datetimes like 2016-02-30 are rejected with ErrInvalidTimeFormat, but the time which
+// doesn't exist or is ambiguous in from follows the normalization of gotime.Date.
+func ConvertTZ(t TimeInternal, from, to *gotime.Location) (mysqlTime, error) {
+	if t.IsNegative() || t.Month() == 0 || t.Day() == 0 {
+		return ZeroTime, errors.Trace(ErrInvalidTimeFormat)
+	}
+	if _, err := checkedMysqlTime(t); err != nil {
+		return ZeroTime, errors.Trace(err)
+	}
+	tm := gotime.Date(t.Year(), gotime.Month(t.Month()), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Microsecond()*1000, from).In(to)
+	year, month, day := tm.Date()
+	hour, minute, second := tm.Clock()
+	if year < 1 || year > 9999 {
+		return ZeroTime, errors.Trace(ErrInvalidTimeFormat)
+	}
+	return newMysqlTime(year, int(month), day, hour, minute, second, tm.Nanosecond()/1000), nil
+}
+
+// LastDay returns the last day of the month of t at 00:00:00, it implements MySQL LAST_DAY.
+func LastDay(t TimeInternal) (mysqlTime, error) {
+	if t.Month() == 0 || t.Day() == 0 || t.Month() > 12 {
+		return ZeroTime, errors.Trace(ErrInvalidTimeFormat)
+	}
+	return newMysqlTime(t.Year(), t.Month(), lastDayOfMonth(t.Year(), t.Month()), 0, 0, 0, 0), nil
+}
+
+// MakeDate returns the date from year and day of year, it implements MySQL MAKEDATE.
+// The day of year larger than the days in the year rolls into the following years.
+func MakeDate(year, dayOfYear int) (mysqlTime, error) {
+	if year < 0 || year > 9999 || dayOfYear <= 0 {
+		return ZeroTime, errors.Trace(ErrInvalidTimeFormat)
+	}
+	year = adjustYear(year)
+	daynr := int64(calcDaynr(year, 1, 1)) + int64(dayOfYear) - 1
+	if daynr > maxDaynr {
+		return ZeroTime, errors.Trace(ErrInvalidTimeFormat)
+	}
+	return FromDays(daynr), nil
+}
+
+// MakeTime returns the TIME value from hour, minute, second and microsecond, it implements MySQL MAKETIME.
+// The hour may be negative or exceed 23, the result is clipped to the TIME range.
+func MakeTime(hour, minute, second int, microsec int) (mysqlTime, error) {
+	if minute < 0 || minute > 59 || second < 0 || second > 59 || microsec < 0 || microsec > 999999 {
+		return ZeroTime, errors.Trace(ErrInvalidTimeFormat)
+	}
+	sign := 1
+	if hour < 0 {
+		hour, sign = -hour, -1
+	}
+	if hour > 838 {
+		// Avoid overflow, the result is clipped to 838:59:59 by SecToTime.
+		hour = 839
+	}
+	return SecToTime(sign*(hour*3600+minute*60+second), sign*microsec), nil
+}
+
+// PeriodAdd adds months to the period in format YYMM or YYYYMM, it implements MySQL PERIOD_ADD.
+func PeriodAdd(period uint64, months int) uint64 {
+	if period == 0 {
+		return 0
+	}
+	return monthToPeriod(periodToMonth(period) + int64(months))
+}
+
+// PeriodDiff returns the number of months between periods p1 and p2, it implements MySQL PERIOD_DIFF.
+func PeriodDiff(p1, p2 uint64) int64 {
+	return periodToMonth(p1) - periodToMonth(p2)
+}
+
+// periodToMonth converts period in format YYMM or YYYYMM to the number of months since year 0.
+func periodToMonth(period uint64) int64 {
+	if period == 0 {
+		return 0
+	}
+	year := int64(adjustYear(int(period / 100)))
+	return year*12 + int64(period%100) - 1
+}
+
+// monthToPeriod converts the number of months since year 0 to period in format YYYYMM.
+func monthToPeriod(month int64) uint64 {
+	if month <= 0 {
+		return 0
+	}
+	year := int64(adjustYear(int(month / 12)))
+	return uint64(year*100 + month%12 + 1)
+}
+
+// isTimeOnly returns whether t has no date part, which means t is a TIME value.
+func isTimeOnly(t TimeInternal) bool {
+	return t.Year() == 0 && t.Month() == 0 && t.Day() == 0
+}
+
+// YearsBetween returns the whole years from t1 to t2 like TIMESTAMPDIFF(YEAR, t1, t2), it can be
+// used to compute the age of someone born at t1. A year is counted only when the month, day and
+// time of t2 reach those of t1, so someone born at 2016-02-29 is 0 at 2017-02-28 and 1 at 2017-03-01.
+// The result is negative if t2 is before t1.
+func YearsBetween(t1, t2 TimeInternal) int64 {
+	return timestampDiff(intervalYEAR, t1, t2)
+}
+
+// DaysBetween returns the calendar days from t1 to t2, the time parts are ignored.
+// It differs from the DAY difference of timestampDiff, which counts the complete 24 hours
+// periods, e.g. from 2016-01-01 23:00:00 to 2016-01-02 01:00:00 DaysBetween is 1 but
+// the DAY difference is 0.
+func DaysBetween(t1, t2 TimeInternal) int {
+	return calcDaynr(t2.Year(), t2.Month(), t2.Day()) - calcDaynr(t1.Year(), t1.Month(), t1.Day())
+}
+
+// timestampDiff returns t2 - t1 in the unit of intervalType, it implements MySQL TIMESTAMPDIFF.
+// The result is truncated toward zero, e.g. the MONTH difference between 2016-01-31 and 2016-02-29 is 0.
+// The difference is calculated in int64, so even MICROSECOND between 0001-01-01 and 9999-12-31,
+// which is about 3.2e17, doesn't overflow.
+func timestampDiff(intervalType string, t1, t2 TimeInternal) int64 {
+	seconds, microseconds, neg := calcTimeDiff(t2, t1, 1)
+	months := uint64(0)
+	switch strings.ToUpper(intervalType) {
+	case intervalYEAR, intervalQUARTER, intervalMONTH:
+		months = calcMonthDiff(t1, t2, neg)
+	}
+
+	sign := int64(1)
+	if neg {
+		sign = -1
+	}
+	secs := int64(seconds)
+	switch strings.ToUpper(intervalType) {
+	case intervalYEAR:
+		return int64(months) / 12 * sign
+	case intervalQUARTER:
+		return int64(months) / 3 * sign
+	case intervalMONTH:
+		return int64(months) * sign
+	case intervalWEEK:
+		return secs / secondsIn24Hour / 7 * sign
+	case intervalDAY:
+		return secs / secondsIn24Hour * sign
+	case intervalHOUR:
+		return secs / 3600 * sign
+	case intervalMINUTE:
+		return secs / 60 * sign
+	case intervalSECOND:
+		return secs * sign
+	case intervalMICROSECOND:
+		// Promote to int64 before multiplying, int may be 32 bits.
+		return (secs*1e6 + int64(microseconds)) * sign
+	}
+	return 0
+}
+
+// timestampAdd adds n units of intervalType to t, it implements MySQL TIMESTAMPADD.
+// Calendar units are added by AddInterval, time units are added directly to the fields in int64
+// so large MICROSECOND amounts don't overflow.
+func timestampAdd(intervalType string, n int64, t TimeInternal) (mysqlTime, error) {
+	mt := newMysqlTime(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Microsecond())
+	if mt.month == 0 || mt.day == 0 {
+		return mt, errors.Trace(ErrInvalidTimeFormat)
+	}
+
+	switch strings.ToUpper(intervalType) {
+	case intervalYEAR, intervalQUARTER, intervalMONTH, intervalWEEK, intervalDAY:
+		if n > math.MaxInt32 || n < math.MinInt32 {
+			return mt, errors.Trace(ErrDatetimeOutOfRange)
+		}
+		return mt.AddInterval(intervalType, int(n))
+	case intervalHOUR:
+		return mt.addDateTime(n/24, n%24*3600, 0)
+	case intervalMINUTE:
+		return mt.addDateTime(n/(24*60), n%(24*60)*60, 0)
+	case intervalSECOND:
+		return mt.addDateTime(n/secondsIn24Hour, n%secondsIn24Hour, 0)
+	case intervalMICROSECOND:
+		return mt.addDateTime(0, n/1e6, n%1e6)
+	}
+	return mt, errors.Errorf("invalid interval unit %s", intervalType)
+}
+
+// timestampDiffFloat is like timestampDiff but keeps the fraction, e.g. the DAY difference of
+// 36 hours is 1.5. The full microsecond difference is divided by the unit length, for
+// YEAR, QUARTER and MONTH which don't have a fixed length, the whole units are returned.
+func timestampDiffFloat(intervalType string, t1, t2 TimeInternal) float64 {
+	var unit int64
+	switch strings.ToUpper(intervalType) {
+	case intervalYEAR, intervalQUARTER, intervalMONTH:
+		return float64(timestampDiff(intervalType, t1, t2))
+	case intervalWEEK:
+		unit = secondsIn24Hour * 7 * 1e6
+	case intervalDAY:
+		unit = secondsIn24Hour * 1e6
+	case intervalHOUR:
+		unit = 3600 * 1e6
+	case intervalMINUTE:
+		unit = 60 * 1e6
+	case intervalSECOND:
+		unit = 1e6
+	case intervalMICROSECOND:
+		unit = 1
+	default:
+		return 0
+	}
+	return float64(timestampDiff(intervalMICROSECOND, t1, t2)) / float64(unit)
+}
+
+// calcMonthDiff returns the number of whole months between t1 and t2, neg means t2 is before t1.
+func calcMonthDiff(t1, t2 TimeInternal, neg bool) uint64 {
+	beg, end := t1, t2
+	if neg {
+		beg, end = t2, t1
+	}
+	yearBeg, monthBeg, dayBeg := uint64(beg.Year()), uint64(beg.Month()), uint64(beg.Day())
+	yearEnd, monthEnd, dayEnd := uint64(end.Year()), uint64(end.Month()), uint64(end.Day())
+	secondBeg := uint64(beg.Hour())*3600 + uint64(beg.Minute())*60 + uint64(beg.Second())
+	secondEnd := uint64(end.Hour())*3600 + uint64(end.Minute())*60 + uint64(end.Second())
+	microsecondBeg, microsecondEnd := uint64(beg.Microsecond()), uint64(end.Microsecond())
+
+	years := yearEnd - yearBeg
+	if monthEnd < monthBeg || (monthEnd == monthBeg && dayEnd < dayBeg) {
+		years--
+	}
+	months := 12 * years
+	if monthEnd < monthBeg || (monthEnd == monthBeg && dayEnd < dayBeg) {
+		months += 12 - (monthBeg - monthEnd)
+	} else {
+		months += monthEnd - monthBeg
+	}
+
+	if dayEnd < dayBeg {
+		months--
+	} else if dayEnd == dayBeg &&
+		(secondEnd < secondBeg || (secondEnd == secondBeg && microsecondEnd < microsecondBeg)) {
+		months--
+	}
+	return months
 }
 
 // calcTimeDiff calculates difference between two datetime values as seconds + microseconds.
 // t1 and t2 should be TIME/DATE/DATETIME value.
 // sign can be +1 or -1, and t2 is preprocessed with sign first.
 func calcTimeDiff(t1, t2 TimeInternal, sign int) (seconds, microseconds int, neg bool) {
-	const secondsIn24Hour = 86400
 	days := calcDaynr(t1.Year(), t1.Month(), t1.Day())
 	days -= sign * calcDaynr(t2.Year(), t2.Month(), t2.Day())
 
@@ -149,10 +1561,26 @@ func calcTimeDiff(t1, t2 TimeInternal, sign int) (seconds, microseconds int, neg
 }
 
 // datetimeToUint64 converts time value to integer in YYYYMMDDHHMMSS format.
+// It never overflows: even with every field at the maximum of its storage type, the date part
+// is below 7e8 and the time part below 5e13, so the result is below 8e14, far from 1.8e19.
+// 9999-12-31 23:59:59 is 99991231235959.
 func datetimeToUint64(t TimeInternal) uint64 {
 	return dateToUint64(t)*1e6 + timeToUint64(t)
 }
 
+// datetimeToUint64WithFsp is like datetimeToUint64 but also returns fsp digits of the microsecond
+// as a separate fraction, e.g. 2016-12-31 23:59:59.123456 with fsp 3 is 20161231235959 and 123.
+// They are not combined into one integer, because YYYYMMDDHHMMSS with 6 more digits doesn't fit
+// in uint64 for years after 1844.
+func datetimeToUint64WithFsp(t TimeInternal, fsp int) (uint64, uint64, error) {
+	fsp, err := checkFsp(fsp)
+	if err != nil {
+		return 0, 0, errors.Trace(err)
+	}
+	frac := uint64(t.Microsecond()) / uint64(math.Pow10(MaxFsp-fsp))
+	return datetimeToUint64(t), frac, nil
+}
+
 // dateToUint64 converts time value to integer in YYYYMMDD format.
 func dateToUint64(t TimeInternal) uint64 {
 	return (uint64)(uint64(t.Year())*10000 +
@@ -160,14 +1588,118 @@ func dateToUint64(t TimeInternal) uint64 {
 		uint64(t.Day()))
 }
 
-// timeToUint64 converts time value to integer in HHMMSS format.
+// timeToUint64 converts time value to integer in HHMMSS format, the hour part may have 3 digits for TIME value.
+// The result is the absolute value for negative TIME value, callers should check the sign by IsNegative.
 func timeToUint64(t TimeInternal) uint64 {
 	return uint64(uint64(t.Hour())*10000 +
 		uint64(t.Minute())*100 +
 		uint64(t.Second()))
 }
 
+// hashSalt is mixed into the time hashes so that they don't equal the plain integer forms.
+const hashSalt uint64 = 0x9e3779b97f4a7c15
+
+// mixHash is the finalizer of splitmix64, it's a bijection so distinct inputs never collide.
+func mixHash(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// HashDate returns a hash of the date part of t for grouping,
+// two values have the same hash if and only if they are on the same calendar date.
+func (t mysqlTime) HashDate() uint64 {
+	return mixHash(dateToUint64(t) ^ hashSalt)
+}
+
+// HashDatetime returns a hash of t for grouping, the microsecond and the sign are folded in.
+func (t mysqlTime) HashDatetime() uint64 {
+	frac := uint64(t.microsecond)
+	if t.neg {
+		frac |= 1 << 32
+	}
+	return mixHash(mixHash(datetimeToUint64(t)^hashSalt) ^ frac)
+}
+
+// ParseDatetimeUint64 parses integer in YYYYMMDDHHMMSS or YYMMDDHHMMSS format, it's the inverse of datetimeToUint64.
+// The date part is parsed by ParseDateUint64, so two digit year is adjusted. Like parseDateTimeFromNum,
+// integer with 8 or less digits is a date in YYYYMMDD or YYMMDD format, e.g. 235959 is 2023-59-59
+// and rejected rather than a time of day. The ranges between the formats are rejected like MySQL
+// number_to_datetime, e.g. 700000 and 1231231. Zero in date like 20160000000000 is allowed, but
+// other invalid fields return ErrInvalidTimeFormat.
+func ParseDatetimeUint64(n uint64) (mysqlTime, error) {
+	if n == 0 {
+		return ZeroTime, nil
+	}
+	switch {
+	case n < 101, // MMDD
+		n > 691231 && n < 700101,      // YYMMDD with year 2000-2069 or 1970-1999
+		n > 991231 && n < 10000101,    // YYYYMMDD
+		n > 99991231 && n < 101000000: // YYMMDDHHMMSS
+		return ZeroTime, errors.Trace(ErrInvalidTimeFormat)
+	}
+	if n <= 99991231 {
+		t, err := ParseDateUint64(n)
+		return t, errors.Trace(err)
+	}
+	hms := n % 1e6
+	hour, minute, second := int(hms/10000), int(hms/100%100), int(hms%100)
+	t, err := ParseDateUint64(n / 1e6)
+	if err != nil {
+		return ZeroTime, errors.Trace(err)
+	}
+	t, err = NewMysqlTimeChecked(int(t.year), int(t.month), int(t.day), hour, minute, second, 0)
+	return t, errors.Trace(err)
+}
+
+// ParseDateUint64 parses integer in YYYYMMDD or YYMMDD format, it's the inverse of dateToUint64.
+// Two digit year in YYMMDD format is adjusted like MySQL, 70-99 to 1970-1999 and 00-69 to 2000-2069.
+func ParseDateUint64(n uint64) (mysqlTime, error) {
+	if n == 0 {
+		return ZeroTime, nil
+	}
+	year, month, day := int(n/10000), int(n/100%100), int(n%100)
+	if n < 1000000 {
+		year = adjustYear(year)
+	}
+	t, err := NewMysqlTimeChecked(year, month, day, 0, 0, 0, 0)
+	return t, errors.Trace(err)
+}
+
+// checkDateFields checks month and day of the date, zero month or day is allowed.
+func checkDateFields(year, month, day int) error {
+	if month > 12 || day > 31 {
+		return ErrInvalidTimeFormat
+	}
+	if month > 0 && day > lastDayOfMonth(year, month) {
+		return ErrInvalidTimeFormat
+	}
+	return nil
+}
+
+const (
+	secondsIn24Hour = 86400
+
+	// minDaynr is the day number of 0001-01-01.
+	minDaynr = 366
+	// maxDaynr is the day number of 9999-12-31.
+	maxDaynr = 3652424
+	// maxDateRangeLen is the maximum number of dates DateRange returns.
+	maxDateRangeLen = 1 << 20
+	// maxIntervalMonths is the number of months from 0000-01 to 9999-12, any interval
+	// longer than it is out of range.
+	maxIntervalMonths = 10000 * 12
+	// maxIntervalSeconds is the number of seconds from 0000-01-01 to 9999-12-31.
+	maxIntervalSeconds = maxDaynr * secondsIn24Hour
+	// maxUnixSeconds is the unix timestamp of 9999-12-31 23:59:59 UTC.
+	maxUnixSeconds = 253402300799
+)
+
 // calcDaynr calculates days since 0000-00-00.
+// Like MySQL, 0000-01-01 is day 1 and year 0 is not a leap year, so 0000-12-31 is day 365.
 func calcDaynr(year, month, day int) int {
 	if year == 0 && month == 0 {
 		return 0
@@ -183,19 +1715,125 @@ func calcDaynr(year, month, day int) int {
 	return delsum + year/4 - temp
 }
 
-// DateDiff calculates number of days between two days.
-func DateDiff(startTime, endTime TimeInternal) int {
-	return calcDaynr(startTime.Year(), startTime.Month(), startTime.Day()) - calcDaynr(endTime.Year(), endTime.Month(), endTime.Day())
+// IsValidDate returns whether month is in range [1, 12] and day is in range [1, last day of the month],
+// taking leap years into account, e.g. 2016-02-29 is valid but 2017-02-29 and 2016-04-31 are not.
+func IsValidDate(year, month, day int) bool {
+	return month >= 1 && month <= 12 && day >= 1 && day <= lastDayOfMonth(year, month)
+}
+
+// IsValidTimeOfDay returns whether the fields make a wall clock time, hour must be in range [0, 23],
+// minute and second in [0, 59] and microsecond in [0, 999999].
+func IsValidTimeOfDay(hour, minute, second, microsecond int) bool {
+	return hour >= 0 && hour <= 23 && minute >= 0 && minute <= 59 &&
+		second >= 0 && second <= 59 && microsecond >= 0 && microsecond <= 999999
+}
+
+// DayNumber returns the days since 0000-00-00 of the date like MySQL TO_DAYS. Unlike calcDaynr,
+// which gives a silently wrong answer for invalid dates, the fields are validated first and
+// an ErrInvalidTimeFormat is returned for dates out of range, like month 13, day 32 or 2017-02-29.
+func DayNumber(year, month, day int) (int, error) {
+	if year < 0 || year > 9999 || !IsValidDate(year, month, day) {
+		return 0, errors.Trace(ErrInvalidTimeFormat)
+	}
+	return calcDaynr(year, month, day), nil
 }
 
-// calcDaysInYear calculates days in one year, it works with 0 <= year <= 99.
+// daysBeforeMonth is the number of days before the first day of each month in a non-leap year.
+var daysBeforeMonth = [12]int{0, 31, 59, 90, 120, 151, 181, 212, 243, 273, 304, 334}
+
+// dayInfo holds the day numbers of a date, so YearDay and week calculation
+// needn't call calcDaynr more than once.
+type dayInfo struct {
+	daynr      int // day number of the date
+	firstDaynr int // day number of the first day of the year
+	yearDay    int // day of the year, in range [1, 366]
+}
+
+func newDayInfo(year, month, day int) dayInfo {
+	daynr := calcDaynr(year, month, day)
+	if month < 1 || month > 12 {
+		firstDaynr := calcDaynr(year, 1, 1)
+		return dayInfo{daynr: daynr, firstDaynr: firstDaynr, yearDay: daynr - firstDaynr + 1}
+	}
+	yearDay := daysBeforeMonth[month-1] + day
+	if month > 2 && IsLeapYear(year) {
+		yearDay++
+	}
+	return dayInfo{daynr: daynr, firstDaynr: daynr - yearDay + 1, yearDay: yearDay}
+}
+
+// DateDiff returns t1 - t2 in days using only the date parts, it implements MySQL DATEDIFF.
+// e.g. DATEDIFF('2016-01-01 23:59', '2015-12-31 00:00') is 1.
+func DateDiff(t1, t2 TimeInternal) int64 {
+	return int64(DaysBetween(t2, t1))
+}
+
+// ToDays returns the day number of t since year 0, it implements MySQL TO_DAYS.
+// Like MySQL, 0000-01-01 is day 1. It returns 0 for dates containing zero month or day.
+func ToDays(t TimeInternal) int64 {
+	if t.Month() == 0 || t.Day() == 0 {
+		return 0
+	}
+	return int64(calcDaynr(t.Year(), t.Month(), t.Day()))
+}
+
+// FromDays converts the day number to a date, it implements MySQL FROM_DAYS.
+// Day numbers out of the range 0001-01-01 to 9999-12-31 are converted to the zero date.
+func FromDays(days int64) mysqlTime {
+	if days < minDaynr || days > maxDaynr {
+		return ZeroTime
+	}
+	year, month, day := getDateFromDaynr(int(days))
+	return newMysqlTime(year, month, day, 0, 0, 0, 0)
+}
+
+// IsLeapYear returns whether year is a leap year in the Gregorian calendar.
+// Like MySQL, year 0 is not treated as a leap year.
+func IsLeapYear(year int) bool {
+	return (year&3) == 0 && (year%100 != 0 || (year%400 == 0 && year != 0))
+}
+
+// calcDaysInYear calculates days in one year, it works with 0 <= year <= 9999.
 func calcDaysInYear(year int) int {
-	if (year&3) == 0 && (year%100 != 0 || (year%400 == 0 && (year != 0))) {
+	if IsLeapYear(year) {
 		return 366
 	}
 	return 365
 }
 
+// lastDayOfMonth returns the last day of the month, month should be in range [1, 12].
+func lastDayOfMonth(year, month int) int {
+	if month == 2 && !IsLeapYear(year) {
+		return 28
+	}
+	return maxDaysInMonth[month-1]
+}
+
+// getDateFromDaynr converts day number to date, it's the inverse of calcDaynr.
+// Day numbers out of range [minDaynr, maxDaynr] are converted to 0000-00-00.
+func getDateFromDaynr(daynr int) (year, month, day int) {
+	if daynr < minDaynr || daynr > maxDaynr {
+		return 0, 0, 0
+	}
+
+	year = daynr * 100 / 36525
+	temp := ((year-1)/100 + 1) * 3 / 4
+	dayOfYear := daynr - year*365 - (year-1)/4 + temp
+	daysInYear := calcDaysInYear(year)
+	for dayOfYear > daysInYear {
+		dayOfYear -= daysInYear
+		year++
+		daysInYear = calcDaysInYear(year)
+	}
+
+	month = 1
+	for dayOfYear > lastDayOfMonth(year, month) {
+		dayOfYear -= lastDayOfMonth(year, month)
+		month++
+	}
+	return year, month, dayOfYear
+}
+
 // calcWeekday calculates weekday from daynr, returns 0 for Monday, 1 for Tuesday ...
 func calcWeekday(daynr int, sundayFirstDayOfWeek bool) int {
 	daynr += 5
@@ -234,8 +1872,8 @@ func weekMode(mode int) weekBehaviour {
 // calcWeek calculates week and year for the time.
 func calcWeek(t *mysqlTime, wb weekBehaviour) (year int, week int) {
 	var days int
-	daynr := calcDaynr(int(t.year), int(t.month), int(t.day))
-	firstDaynr := calcDaynr(int(t.year), 1, 1)
+	info := newDayInfo(int(t.year), int(t.month), int(t.day))
+	daynr, firstDaynr := info.daynr, info.firstDaynr
 	mondayFirst := wb.test(weekBehaviourMondayFirst)
 	weekYear := wb.test(weekBehaviourYear)
 	firstWeekday := wb.test(weekBehaviourFirstWeekday)
@@ -13,7 +13,21 @@
 package types
 
 import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	gotime "time"
+
 	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/mysql"
+	"github.com/pingcap/tidb/terror"
 )
 
 type testMyTimeSuite struct{}
@@ -36,9 +50,9 @@ func (s *testMyTimeSuite) TestWeek(c *C) {
 		Mode   int
 		Expect int
 	}{
-		{mysqlTime{2008, 2, 20, 0, 0, 0, 0}, 0, 7},
-		{mysqlTime{2008, 2, 20, 0, 0, 0, 0}, 1, 8},
-		{mysqlTime{2008, 12, 31, 0, 0, 0, 0}, 1, 53},
+		{newMysqlTime(2008, 2, 20, 0, 0, 0, 0), 0, 7},
+		{newMysqlTime(2008, 2, 20, 0, 0, 0, 0), 1, 8},
+		{newMysqlTime(2008, 12, 31, 0, 0, 0, 0), 1, 53},
 	}
 
 	for ith, t := range cases {
@@ -65,16 +79,16 @@ func (s *testMyTimeSuite) TestCalcTimeDiff(c *C) {
 	}{
 		// calcTimeDiff can be used for month = 0.
 		{
-			mysqlTime{2006, 0, 1, 12, 23, 21, 0},
-			mysqlTime{2006, 0, 3, 21, 23, 22, 0},
+			newMysqlTime(2006, 0, 1, 12, 23, 21, 0),
+			newMysqlTime(2006, 0, 3, 21, 23, 22, 0),
 			1,
-			mysqlTime{0, 0, 0, 57, 0, 1, 0},
+			newMysqlTime(0, 0, 0, 57, 0, 1, 0),
 		},
 		{
-			mysqlTime{0, 0, 0, 21, 23, 24, 0},
-			mysqlTime{0, 0, 0, 11, 23, 22, 0},
+			newMysqlTime(0, 0, 0, 21, 23, 24, 0),
+			newMysqlTime(0, 0, 0, 11, 23, 22, 0),
 			1,
-			mysqlTime{0, 0, 0, 10, 0, 2, 0},
+			newMysqlTime(0, 0, 0, 10, 0, 2, 0),
 		},
 	}
 
@@ -92,11 +106,11 @@ func (s *testMyTimeSuite) TestCompareTime(c *C) {
 		T2     mysqlTime
 		Expect int
 	}{
-		{mysqlTime{0, 0, 0, 0, 0, 0, 0}, mysqlTime{0, 0, 0, 0, 0, 0, 0}, 0},
-		{mysqlTime{0, 0, 0, 0, 1, 0, 0}, mysqlTime{0, 0, 0, 0, 0, 0, 0}, 1},
-		{mysqlTime{2006, 1, 2, 3, 4, 5, 6}, mysqlTime{2016, 1, 2, 3, 4, 5, 0}, -1},
-		{mysqlTime{0, 0, 0, 11, 22, 33, 0}, mysqlTime{0, 0, 0, 12, 21, 33, 0}, -1},
-		{mysqlTime{9999, 12, 30, 23, 59, 59, 999999}, mysqlTime{0, 1, 2, 3, 4, 5, 6}, 1},
+		{newMysqlTime(0, 0, 0, 0, 0, 0, 0), newMysqlTime(0, 0, 0, 0, 0, 0, 0), 0},
+		{newMysqlTime(0, 0, 0, 0, 1, 0, 0), newMysqlTime(0, 0, 0, 0, 0, 0, 0), 1},
+		{newMysqlTime(2006, 1, 2, 3, 4, 5, 6), newMysqlTime(2016, 1, 2, 3, 4, 5, 0), -1},
+		{newMysqlTime(0, 0, 0, 11, 22, 33, 0), newMysqlTime(0, 0, 0, 12, 21, 33, 0), -1},
+		{newMysqlTime(9999, 12, 30, 23, 59, 59, 999999), newMysqlTime(0, 1, 2, 3, 4, 5, 6), 1},
 	}
 
 	for _, t := range cases {
@@ -104,3 +118,3026 @@ func (s *testMyTimeSuite) TestCompareTime(c *C) {
 		c.Assert(compareTime(&t.T2, &t.T1), Equals, -t.Expect)
 	}
 }
+
+func (s *testMyTimeSuite) TestFormat(c *C) {
+	cases := []struct {
+		Input  mysqlTime
+		Layout string
+		Expect string
+	}{
+		{newMysqlTime(2010, 1, 7, 23, 12, 34, 12345), "%Y %y %m %c %d %e %D", "2010 10 01 1 07 7 7th"},
+		{newMysqlTime(2010, 1, 7, 23, 12, 34, 12345), "%H %k %h %I %l %i %s %S %f", "23 23 11 11 11 12 34 34 012345"},
+		{newMysqlTime(2010, 1, 7, 23, 12, 34, 12345), "%p %r %T", "PM 11:12:34 PM 23:12:34"},
+		{newMysqlTime(2010, 1, 7, 23, 12, 34, 12345), "%W %a %w %M %b %j", "Thursday Thu 4 January Jan 007"},
+		{newMysqlTime(2010, 1, 7, 23, 12, 34, 12345), "%U %u %V %v %X %x", "01 01 01 01 2010 2010"},
+		{newMysqlTime(2010, 1, 2, 0, 5, 6, 0), "%U %u %V %v %X %x", "00 00 52 53 2009 2009"},
+		{newMysqlTime(2010, 1, 2, 0, 5, 6, 0), "%h %l %p %r %f", "12 12 AM 12:05:06 AM 000000"},
+		{newMysqlTime(2012, 12, 21, 12, 0, 0, 1), "%h %p %r %f %D", "12 PM 12:00:00 PM 000001 21st"},
+		{newMysqlTime(2016, 0, 0, 0, 0, 0, 0), "[%W][%a]", "[][]"},
+		{newMysqlTime(2016, 10, 0, 0, 0, 0, 0), "[%W][%a]", "[][]"},
+		{newMysqlTime(2016, 10, 3, 0, 0, 0, 0), "%% %z %Q abc", "% z Q abc"},
+		// Week specifiers near year boundaries, %U, %u, %V and %v are WEEK modes 0, 1, 2 and 3,
+		// %X and %x are the years of %V and %v. The results are from MySQL.
+		{newMysqlTime(2016, 1, 1, 0, 0, 0, 0), "%U %u %V %v %X %x", "00 00 52 53 2015 2015"},
+		{newMysqlTime(2016, 12, 31, 0, 0, 0, 0), "%U %u %V %v %X %x", "52 52 52 52 2016 2016"},
+		{newMysqlTime(2017, 1, 1, 0, 0, 0, 0), "%U %u %V %v %X %x", "01 00 01 52 2017 2016"},
+		{newMysqlTime(2014, 12, 29, 0, 0, 0, 0), "%U %u %V %v %X %x", "52 53 52 01 2014 2015"},
+		// The day of year is padded to three digits.
+		{newMysqlTime(2016, 1, 1, 0, 0, 0, 0), "%j", "001"},
+		{newMysqlTime(2016, 1, 10, 0, 0, 0, 0), "%j", "010"},
+		{newMysqlTime(2016, 2, 29, 0, 0, 0, 0), "%j", "060"},
+		{newMysqlTime(2015, 12, 31, 0, 0, 0, 0), "%j", "365"},
+		{newMysqlTime(2016, 12, 31, 0, 0, 0, 0), "%j", "366"},
+	}
+
+	for i, t := range cases {
+		str, err := t.Input.Format(t.Layout)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(str, Equals, t.Expect, Commentf("%d failed.", i))
+	}
+}
+
+func (s *testMyTimeSuite) TestWeekday(c *C) {
+	cases := []mysqlTime{
+		newMysqlTime(1970, 1, 1, 0, 0, 0, 0),
+		newMysqlTime(2008, 2, 29, 23, 59, 59, 999999),
+		newMysqlTime(2016, 3, 13, 2, 30, 0, 0),
+		newMysqlTime(2016, 11, 6, 1, 30, 0, 0),
+		newMysqlTime(2016, 12, 31, 0, 0, 0, 0),
+	}
+
+	for _, name := range []string{"UTC", "America/New_York", "Asia/Shanghai"} {
+		loc, err := gotime.LoadLocation(name)
+		c.Assert(err, IsNil)
+		for i, t := range cases {
+			expect := gotime.Date(t.Year(), gotime.Month(t.Month()), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Microsecond()*1000, loc).Weekday()
+			c.Assert(t.Weekday(), Equals, expect, Commentf("%s %d failed.", name, i))
+		}
+	}
+
+	// Weekday is the same as the Go time based calculation.
+	for daynr := calcDaynr(2016, 1, 1); daynr <= calcDaynr(2016, 12, 31); daynr++ {
+		t := FromDays(int64(daynr))
+		t1, err := t.GoTime(gotime.Local)
+		c.Assert(err, IsNil)
+		c.Assert(t.Weekday(), Equals, t1.Weekday(), Commentf("%d failed.", daynr))
+	}
+
+	// Dates containing zero month or day.
+	c.Assert(newMysqlTime(0, 1, 0, 0, 0, 0, 0).Weekday(), Equals, gotime.Saturday)
+	c.Assert(newMysqlTime(2016, 12, 0, 0, 0, 0, 0).Weekday(), Equals, gotime.Wednesday)
+}
+
+func (s *testMyTimeSuite) TestDayOfWeek(c *C) {
+	tbl := []struct {
+		t      mysqlTime
+		expect int
+	}{
+		{newMysqlTime(2016, 12, 25, 0, 0, 0, 0), 1},
+		{newMysqlTime(2016, 12, 26, 12, 0, 0, 0), 2},
+		{newMysqlTime(2016, 12, 31, 23, 59, 59, 0), 7},
+		{newMysqlTime(1970, 1, 1, 0, 0, 0, 0), 5},
+		{newMysqlTime(2000, 2, 29, 0, 0, 0, 0), 3},
+		{ZeroTime, 0},
+		{newMysqlTime(2016, 12, 0, 0, 0, 0, 0), 0},
+	}
+
+	for i, t := range tbl {
+		c.Assert(t.t.DayOfWeek(), Equals, t.expect, Commentf("%d failed.", i))
+	}
+}
+
+func (s *testMyTimeSuite) TestWeekOfYear(c *C) {
+	tbl := []struct {
+		t      mysqlTime
+		expect int
+	}{
+		{newMysqlTime(2016, 1, 1, 0, 0, 0, 0), 53},
+		{newMysqlTime(2015, 12, 31, 0, 0, 0, 0), 53},
+		{newMysqlTime(2016, 1, 4, 0, 0, 0, 0), 1},
+		{newMysqlTime(2014, 12, 29, 0, 0, 0, 0), 1},
+		{newMysqlTime(2017, 1, 1, 0, 0, 0, 0), 52},
+		{newMysqlTime(2008, 2, 20, 0, 0, 0, 0), 8},
+	}
+
+	for i, t := range tbl {
+		c.Assert(t.t.WeekOfYear(), Equals, t.expect, Commentf("%d failed.", i))
+	}
+
+	// WeekOfYear is the same as the ISO 8601 week.
+	for daynr := calcDaynr(2000, 1, 1); daynr <= calcDaynr(2030, 12, 31); daynr++ {
+		t := FromDays(int64(daynr))
+		_, expect := gotime.Date(t.Year(), gotime.Month(t.Month()), t.Day(), 0, 0, 0, 0, gotime.UTC).ISOWeek()
+		c.Assert(t.WeekOfYear(), Equals, expect, Commentf("%d failed.", daynr))
+	}
+}
+
+func (s *testMyTimeSuite) TestIsLeapYear(c *C) {
+	tbl := []struct {
+		year   int
+		expect bool
+	}{
+		{0, false},
+		{4, true},
+		{100, false},
+		{400, true},
+		{1900, false},
+		{1996, true},
+		{2000, true},
+		{2015, false},
+		{2016, true},
+		{2100, false},
+		{2400, true},
+		{9996, true},
+		{9999, false},
+	}
+
+	for i, t := range tbl {
+		c.Assert(IsLeapYear(t.year), Equals, t.expect, Commentf("%d failed.", i))
+		days := 365
+		if t.expect {
+			days = 366
+		}
+		c.Assert(calcDaysInYear(t.year), Equals, days, Commentf("%d failed.", i))
+	}
+}
+
+func (s *testMyTimeSuite) TestWeekCenturyBoundary(c *C) {
+	// 1900 and 2100 are not leap years, they are the boundaries where
+	// a wrong days in year would break the week calculation.
+	tbl := []struct {
+		t         mysqlTime
+		week0     int
+		yearWeek0 int
+		yearWeek3 int
+	}{
+		{newMysqlTime(1899, 12, 31, 0, 0, 0, 0), 53, 189953, 189952},
+		{newMysqlTime(1900, 1, 1, 0, 0, 0, 0), 0, 189953, 190001},
+		{newMysqlTime(1900, 1, 7, 0, 0, 0, 0), 1, 190001, 190001},
+		{newMysqlTime(1900, 12, 31, 0, 0, 0, 0), 52, 190052, 190101},
+		{newMysqlTime(2099, 12, 31, 0, 0, 0, 0), 52, 209952, 209953},
+		{newMysqlTime(2100, 1, 1, 0, 0, 0, 0), 0, 209952, 209953},
+		{newMysqlTime(2100, 1, 3, 0, 0, 0, 0), 1, 210001, 209953},
+		{newMysqlTime(2100, 12, 31, 0, 0, 0, 0), 52, 210052, 210052},
+	}
+
+	for i, t := range tbl {
+		c.Assert(t.t.Week(0), Equals, t.week0, Commentf("%d failed.", i))
+		year, week := t.t.YearWeek(0)
+		c.Assert(year*100+week, Equals, t.yearWeek0, Commentf("%d failed.", i))
+		year, week = t.t.YearWeek(3)
+		c.Assert(year*100+week, Equals, t.yearWeek3, Commentf("%d failed.", i))
+	}
+}
+
+func (s *testMyTimeSuite) TestGetDateFromDaynr(c *C) {
+	for daynr := minDaynr; daynr <= maxDaynr; daynr++ {
+		year, month, day := getDateFromDaynr(daynr)
+		c.Assert(calcDaynr(year, month, day), Equals, daynr)
+	}
+
+	year, month, day := getDateFromDaynr(minDaynr - 1)
+	c.Assert([]int{year, month, day}, DeepEquals, []int{0, 0, 0})
+	year, month, day = getDateFromDaynr(maxDaynr + 1)
+	c.Assert([]int{year, month, day}, DeepEquals, []int{0, 0, 0})
+}
+
+func (s *testMyTimeSuite) TestAddInterval(c *C) {
+	cases := []struct {
+		Input  mysqlTime
+		Unit   string
+		Amount int
+		Expect mysqlTime
+	}{
+		{newMysqlTime(2016, 1, 31, 10, 0, 0, 0), "MONTH", 1, newMysqlTime(2016, 2, 29, 10, 0, 0, 0)},
+		{newMysqlTime(2015, 1, 31, 10, 0, 0, 0), "MONTH", 1, newMysqlTime(2015, 2, 28, 10, 0, 0, 0)},
+		{newMysqlTime(2016, 3, 31, 0, 0, 0, 0), "MONTH", -1, newMysqlTime(2016, 2, 29, 0, 0, 0, 0)},
+		{newMysqlTime(2016, 11, 30, 0, 0, 0, 0), "MONTH", 14, newMysqlTime(2018, 1, 30, 0, 0, 0, 0)},
+		{newMysqlTime(2016, 2, 29, 0, 0, 0, 0), "YEAR", 1, newMysqlTime(2017, 2, 28, 0, 0, 0, 0)},
+		{newMysqlTime(2016, 2, 29, 0, 0, 0, 0), "year", 4, newMysqlTime(2020, 2, 29, 0, 0, 0, 0)},
+		{newMysqlTime(2016, 2, 28, 0, 0, 0, 0), "DAY", 1, newMysqlTime(2016, 2, 29, 0, 0, 0, 0)},
+		{newMysqlTime(2016, 12, 31, 0, 0, 0, 0), "DAY", 1, newMysqlTime(2017, 1, 1, 0, 0, 0, 0)},
+		{newMysqlTime(1960, 3, 1, 0, 0, 0, 0), "DAY", -1, newMysqlTime(1960, 2, 29, 0, 0, 0, 0)},
+		{newMysqlTime(2016, 12, 31, 23, 0, 0, 0), "HOUR", 25, newMysqlTime(2017, 1, 2, 0, 0, 0, 0)},
+		{newMysqlTime(2016, 1, 1, 0, 0, 0, 0), "MINUTE", -1, newMysqlTime(2015, 12, 31, 23, 59, 0, 0)},
+		{newMysqlTime(2016, 2, 28, 23, 59, 59, 0), "SECOND", 1, newMysqlTime(2016, 2, 29, 0, 0, 0, 0)},
+		{newMysqlTime(2016, 12, 31, 23, 59, 59, 999999), "MICROSECOND", 1, newMysqlTime(2017, 1, 1, 0, 0, 0, 0)},
+		{newMysqlTime(2017, 1, 1, 0, 0, 0, 0), "MICROSECOND", -1, newMysqlTime(2016, 12, 31, 23, 59, 59, 999999)},
+		{newMysqlTime(2016, 1, 1, 0, 0, 0, 500000), "MICROSECOND", 1500000, newMysqlTime(2016, 1, 1, 0, 0, 2, 0)},
+	}
+
+	for i, t := range cases {
+		result, err := t.Input.AddInterval(t.Unit, t.Amount)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(result, Equals, t.Expect, Commentf("%d failed.", i))
+	}
+
+	errCases := []struct {
+		Input  mysqlTime
+		Unit   string
+		Amount int
+	}{
+		{newMysqlTime(9999, 12, 31, 0, 0, 0, 0), "DAY", 1},
+		{newMysqlTime(9999, 12, 31, 0, 0, 0, 0), "MONTH", 1},
+		{newMysqlTime(1, 1, 1, 0, 0, 0, 0), "SECOND", -1},
+		{newMysqlTime(0, 1, 1, 0, 0, 0, 0), "YEAR", -1},
+		{newMysqlTime(2016, 0, 1, 0, 0, 0, 0), "DAY", 1},
+		{newMysqlTime(2016, 1, 0, 0, 0, 0, 0), "DAY", 1},
+		{newMysqlTime(2016, 1, 1, 0, 0, 0, 0), "FORTNIGHT", 1},
+	}
+	for i, t := range errCases {
+		_, err := t.Input.AddInterval(t.Unit, t.Amount)
+		c.Assert(err, NotNil, Commentf("%d failed.", i))
+	}
+
+	// Huge amounts must not overflow into a valid result.
+	overflowCases := []struct {
+		Unit   string
+		Amount int
+	}{
+		{"YEAR", 10000},
+		{"YEAR", -10000},
+		{"YEAR", 1537228672809129301},
+		{"YEAR", math.MaxInt64},
+		{"YEAR", math.MinInt64},
+		{"DAY", math.MaxInt64},
+		{"DAY", math.MinInt64},
+	}
+	for i, t := range overflowCases {
+		_, err := newMysqlTime(2016, 1, 1, 0, 0, 0, 0).AddInterval(t.Unit, t.Amount)
+		c.Assert(terror.ErrorEqual(err, ErrDatetimeOutOfRange), IsTrue, Commentf("%d failed.", i))
+	}
+	v, err := newMysqlTime(1, 1, 1, 0, 0, 0, 0).AddInterval("YEAR", 9998)
+	c.Assert(err, IsNil)
+	c.Assert(v, Equals, newMysqlTime(9999, 1, 1, 0, 0, 0, 0))
+}
+
+func (s *testMyTimeSuite) TestAddIntervalQuarter(c *C) {
+	cases := []struct {
+		Input  mysqlTime
+		Amount int
+		Expect mysqlTime
+	}{
+		{newMysqlTime(2016, 11, 30, 0, 0, 0, 0), 1, newMysqlTime(2017, 2, 28, 0, 0, 0, 0)},
+		{newMysqlTime(2015, 11, 30, 0, 0, 0, 0), 1, newMysqlTime(2016, 2, 29, 0, 0, 0, 0)},
+		{newMysqlTime(2016, 5, 31, 12, 30, 0, 1), -1, newMysqlTime(2016, 2, 29, 12, 30, 0, 1)},
+		{newMysqlTime(2016, 1, 15, 0, 0, 0, 0), 4, newMysqlTime(2017, 1, 15, 0, 0, 0, 0)},
+		{newMysqlTime(2016, 1, 15, 0, 0, 0, 0), -5, newMysqlTime(2014, 10, 15, 0, 0, 0, 0)},
+		{newMysqlTime(2016, 1, 15, 0, 0, 0, 0), 0, newMysqlTime(2016, 1, 15, 0, 0, 0, 0)},
+	}
+
+	for i, t := range cases {
+		result, err := t.Input.AddInterval("QUARTER", t.Amount)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(result, Equals, t.Expect, Commentf("%d failed.", i))
+		// A quarter is the same as 3 months.
+		months, err := t.Input.AddInterval("MONTH", t.Amount*3)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(result, Equals, months, Commentf("%d failed.", i))
+	}
+
+	_, err := newMysqlTime(9999, 10, 1, 0, 0, 0, 0).AddInterval("QUARTER", 1)
+	c.Assert(err, NotNil)
+	for _, amount := range []int{40000, -40000, 3074457345618258603, math.MaxInt64, math.MinInt64} {
+		_, err = newMysqlTime(2016, 1, 1, 0, 0, 0, 0).AddInterval("QUARTER", amount)
+		c.Assert(terror.ErrorEqual(err, ErrDatetimeOutOfRange), IsTrue, Commentf("%d failed.", amount))
+	}
+}
+
+func (s *testMyTimeSuite) TestAddIntervalWeek(c *C) {
+	cases := []struct {
+		Input  mysqlTime
+		Amount int
+		Expect mysqlTime
+	}{
+		{newMysqlTime(2016, 2, 25, 10, 0, 0, 0), 1, newMysqlTime(2016, 3, 3, 10, 0, 0, 0)},
+		{newMysqlTime(2015, 2, 25, 10, 0, 0, 0), 1, newMysqlTime(2015, 3, 4, 10, 0, 0, 0)},
+		{newMysqlTime(2016, 3, 3, 0, 0, 0, 0), -1, newMysqlTime(2016, 2, 25, 0, 0, 0, 0)},
+		{newMysqlTime(2016, 12, 28, 23, 59, 59, 999999), 1, newMysqlTime(2017, 1, 4, 23, 59, 59, 999999)},
+		{newMysqlTime(2017, 1, 4, 0, 0, 0, 0), -1, newMysqlTime(2016, 12, 28, 0, 0, 0, 0)},
+		{newMysqlTime(2016, 1, 1, 0, 0, 0, 0), 52, newMysqlTime(2016, 12, 30, 0, 0, 0, 0)},
+	}
+
+	for i, t := range cases {
+		result, err := t.Input.AddInterval("WEEK", t.Amount)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(result, Equals, t.Expect, Commentf("%d failed.", i))
+	}
+
+	_, err := newMysqlTime(9999, 12, 25, 0, 0, 0, 0).AddInterval("WEEK", 1)
+	c.Assert(err, NotNil)
+	_, err = newMysqlTime(1, 1, 5, 0, 0, 0, 0).AddInterval("WEEK", -1)
+	c.Assert(err, NotNil)
+	for _, amount := range []int{7905747460161236407, math.MaxInt64, math.MinInt64} {
+		_, err = newMysqlTime(2016, 1, 1, 0, 0, 0, 0).AddInterval("WEEK", amount)
+		c.Assert(terror.ErrorEqual(err, ErrDatetimeOutOfRange), IsTrue, Commentf("%d failed.", amount))
+	}
+}
+
+func (s *testMyTimeSuite) TestQuarter(c *C) {
+	expects := []int{0, 1, 1, 1, 2, 2, 2, 3, 3, 3, 4, 4, 4}
+	for month, expect := range expects {
+		t := newMysqlTime(2016, month, 1, 0, 0, 0, 0)
+		c.Assert(t.Quarter(), Equals, expect, Commentf("month %d failed.", month))
+	}
+}
+
+func (s *testMyTimeSuite) TestToDays(c *C) {
+	c.Assert(ToDays(newMysqlTime(2007, 10, 7, 0, 0, 0, 0)), Equals, int64(733321))
+	c.Assert(ToDays(newMysqlTime(1, 1, 1, 0, 0, 0, 0)), Equals, int64(366))
+	c.Assert(ToDays(newMysqlTime(0, 1, 1, 0, 0, 0, 0)), Equals, int64(1))
+	c.Assert(ToDays(newMysqlTime(0, 12, 31, 0, 0, 0, 0)), Equals, int64(365))
+	c.Assert(ToDays(newMysqlTime(2007, 0, 7, 0, 0, 0, 0)), Equals, int64(0))
+	c.Assert(ToDays(ZeroTime), Equals, int64(0))
+
+	c.Assert(FromDays(733321), Equals, newMysqlTime(2007, 10, 7, 0, 0, 0, 0))
+	c.Assert(FromDays(730669), Equals, newMysqlTime(2000, 7, 3, 0, 0, 0, 0))
+	c.Assert(FromDays(365), Equals, ZeroTime)
+	c.Assert(FromDays(-1), Equals, ZeroTime)
+	c.Assert(FromDays(maxDaynr+1), Equals, ZeroTime)
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100000; i++ {
+		year := r.Intn(9999) + 1
+		month := r.Intn(12) + 1
+		day := r.Intn(lastDayOfMonth(year, month)) + 1
+		t := newMysqlTime(year, month, day, 0, 0, 0, 0)
+		c.Assert(FromDays(ToDays(t)), Equals, t)
+	}
+}
+
+func (s *testMyTimeSuite) TestSecToTime(c *C) {
+	cases := []struct {
+		Seconds      int
+		Microseconds int
+		Expect       mysqlTime
+	}{
+		{0, 0, newMysqlTime(0, 0, 0, 0, 0, 0, 0)},
+		{2378, 0, newMysqlTime(0, 0, 0, 0, 39, 38, 0)},
+		{2378, 500000, newMysqlTime(0, 0, 0, 0, 39, 38, 500000)},
+		{86400, 0, newMysqlTime(0, 0, 0, 24, 0, 0, 0)},
+		{3020398, 999999, newMysqlTime(0, 0, 0, 838, 59, 58, 999999)},
+		{3020399, 0, newMysqlTime(0, 0, 0, 838, 59, 59, 0)},
+		{3020399, 1, newMysqlTime(0, 0, 0, 838, 59, 59, 0)},
+		{3020400, 0, newMysqlTime(0, 0, 0, 838, 59, 59, 0)},
+		{math.MaxInt64, math.MaxInt64, newMysqlTime(0, 0, 0, 838, 59, 59, 0)},
+		// The microseconds are carried into the seconds.
+		{0, 1500000, newMysqlTime(0, 0, 0, 0, 0, 1, 500000)},
+		{59, 2000001, newMysqlTime(0, 0, 0, 0, 1, 1, 1)},
+		{3020398, 1000000, newMysqlTime(0, 0, 0, 838, 59, 59, 0)},
+		{3020399, -1000000, newMysqlTime(0, 0, 0, 838, 59, 58, 0)},
+		{3020401, -2000000, newMysqlTime(0, 0, 0, 838, 59, 59, 0)},
+		// Mixed signs are summed before taking the sign.
+		{1, -500000, newMysqlTime(0, 0, 0, 0, 0, 0, 500000)},
+		{-1, 500000, mysqlTime{microsecond: 500000, neg: true}},
+		{-1, -500000, mysqlTime{second: 1, microsecond: 500000, neg: true}},
+		{-2, 1500000, mysqlTime{microsecond: 500000, neg: true}},
+		{-3020400, 0, mysqlTime{hour: 838, minute: 59, second: 59, neg: true}},
+		{-3020399, -1, mysqlTime{hour: 838, minute: 59, second: 59, neg: true}},
+		{math.MinInt64, math.MinInt64, mysqlTime{hour: 838, minute: 59, second: 59, neg: true}},
+	}
+
+	for i, t := range cases {
+		result := SecToTime(t.Seconds, t.Microseconds)
+		c.Assert(result, Equals, t.Expect, Commentf("%d failed.", i))
+	}
+	str, err := SecToTime(-1, 500000).Format("%H:%i:%s.%f")
+	c.Assert(err, IsNil)
+	c.Assert(str, Equals, "-00:00:00.500000")
+	for i, t := range []struct {
+		layout string
+		expect string
+	}{
+		{"%H:%i:%s", "-12:30:00"},
+		{"%T", "-12:30:00"},
+		{"%Y-%m-%d %H:%i", "0000-00-00 -12:30"},
+		{"%i:%s", "-30:00"},
+		{"%Y-%m-%d", "0000-00-00"},
+	} {
+		str, err = SecToTime(-45000, 0).Format(t.layout)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(str, Equals, t.expect, Commentf("%d failed.", i))
+	}
+
+	c.Assert(TimeToSec(newMysqlTime(0, 0, 0, 22, 23, 0, 0)), Equals, int64(80580))
+	c.Assert(TimeToSec(newMysqlTime(0, 0, 0, 0, 39, 38, 500000)), Equals, int64(2378))
+	c.Assert(TimeToSec(newMysqlTime(0, 0, 0, 838, 59, 59, 0)), Equals, int64(3020399))
+	c.Assert(TimeToSec(newMysqlTime(2016, 12, 31, 1, 0, 0, 0)), Equals, int64(3600))
+}
+
+func (s *testMyTimeSuite) TestTimeRange(c *C) {
+	maxTime := SecToTime(838*3600+59*60+59, 0)
+	c.Assert(maxTime.Hour(), Equals, 838)
+	c.Assert(maxTime.Minute(), Equals, 59)
+	c.Assert(maxTime.Second(), Equals, 59)
+	c.Assert(maxTime.neg, IsFalse)
+	c.Assert(timeToUint64(maxTime), Equals, uint64(8385959))
+	_, err := maxTime.GoTime(gotime.UTC)
+	c.Assert(err, NotNil)
+
+	minTime := SecToTime(-(838*3600 + 59*60 + 59), 0)
+	c.Assert(minTime.Hour(), Equals, 838)
+	c.Assert(minTime.Minute(), Equals, 59)
+	c.Assert(minTime.Second(), Equals, 59)
+	c.Assert(minTime.neg, IsTrue)
+	c.Assert(SecToTime(-3020400, 0), Equals, minTime)
+	c.Assert(SecToTime(-3020399, -1), Equals, minTime)
+	_, err = minTime.GoTime(gotime.UTC)
+	c.Assert(err, NotNil)
+
+	t := SecToTime(24*3600, 0)
+	c.Assert(t.Hour(), Equals, 24)
+	c.Assert(timeToUint64(t), Equals, uint64(240000))
+	_, err = t.GoTime(gotime.UTC)
+	c.Assert(err, NotNil)
+	_, err = newMysqlTime(2016, 12, 31, 24, 0, 0, 0).GoTime(gotime.UTC)
+	c.Assert(err, NotNil)
+	_, err = newMysqlTime(2016, 12, 31, 23, 59, 59, 0).GoTime(gotime.UTC)
+	c.Assert(err, IsNil)
+
+	var result mysqlTime
+	calcTimeFromSec(&result, -45000, -500000)
+	c.Assert(result, Equals, mysqlTime{hour: 12, minute: 30, microsecond: 500000, neg: true})
+}
+
+func (s *testMyTimeSuite) TestNegativeTime(c *C) {
+	cases := []struct {
+		T1     mysqlTime
+		T2     mysqlTime
+		Expect string
+	}{
+		{newMysqlTime(0, 0, 0, 10, 0, 0, 0), newMysqlTime(0, 0, 0, 12, 0, 0, 0), "-02:00:00.000000"},
+		{newMysqlTime(0, 0, 0, 12, 0, 0, 0), newMysqlTime(0, 0, 0, 10, 0, 0, 0), "02:00:00.000000"},
+		{newMysqlTime(0, 0, 0, 10, 0, 0, 0), newMysqlTime(0, 0, 0, 10, 0, 0, 500000), "-00:00:00.500000"},
+		{newMysqlTime(2016, 1, 1, 0, 0, 0, 0), newMysqlTime(2016, 1, 2, 1, 0, 0, 0), "-25:00:00.000000"},
+	}
+
+	for i, t := range cases {
+		seconds, microseconds, neg := calcTimeDiff(t.T1, t.T2, 1)
+		if neg {
+			seconds, microseconds = -seconds, -microseconds
+		}
+		var result mysqlTime
+		calcTimeFromSec(&result, seconds, microseconds)
+		c.Assert(result.IsNegative(), Equals, neg, Commentf("%d failed.", i))
+		str, err := result.Format("%H:%i:%s.%f")
+		c.Assert(err, IsNil)
+		c.Assert(str, Equals, t.Expect, Commentf("%d failed.", i))
+	}
+
+	neg := SecToTime(-7200, 0)
+	pos := SecToTime(7200, 0)
+	c.Assert(neg.IsNegative(), IsTrue)
+	c.Assert(TimeToSec(neg), Equals, int64(-7200))
+	c.Assert(timeToUint64(neg), Equals, uint64(20000))
+	c.Assert(compareTime(neg, pos), Equals, -1)
+	c.Assert(compareTime(pos, neg), Equals, 1)
+	c.Assert(compareTime(neg, SecToTime(-7200, 0)), Equals, 0)
+	c.Assert(compareTime(neg, SecToTime(-3600, 0)), Equals, -1)
+	c.Assert(compareTime(neg, SecToTime(-7200, -1)), Equals, 1)
+	c.Assert(compareTime(neg, ZeroTime), Equals, -1)
+}
+
+func (s *testMyTimeSuite) TestTimeDiff(c *C) {
+	cases := []struct {
+		T1     mysqlTime
+		T2     mysqlTime
+		Expect string
+	}{
+		// TIMEDIFF('10:00', '12:00')
+		{newMysqlTime(0, 0, 0, 10, 0, 0, 0), newMysqlTime(0, 0, 0, 12, 0, 0, 0), "-02:00:00.000000"},
+		// TIMEDIFF('2000:01:01 00:00:00', '2000:01:01 00:00:00.000001')
+		{newMysqlTime(2000, 1, 1, 0, 0, 0, 0), newMysqlTime(2000, 1, 1, 0, 0, 0, 1), "-00:00:00.000001"},
+		// TIMEDIFF('2008-12-31 23:59:59.000001', '2008-12-30 01:01:01.000002')
+		{newMysqlTime(2008, 12, 31, 23, 59, 59, 1), newMysqlTime(2008, 12, 30, 1, 1, 1, 2), "46:58:57.999999"},
+		// TIMEDIFF('23:59:59.5', '00:00:00.5')
+		{newMysqlTime(0, 0, 0, 23, 59, 59, 500000), newMysqlTime(0, 0, 0, 0, 0, 0, 500000), "23:59:59.000000"},
+		// TIMEDIFF('2016-01-01 00:00:00', '2015-01-01 00:00:00') is clipped.
+		{newMysqlTime(2016, 1, 1, 0, 0, 0, 0), newMysqlTime(2015, 1, 1, 0, 0, 0, 0), "838:59:59.000000"},
+		{newMysqlTime(2015, 1, 1, 0, 0, 0, 0), newMysqlTime(2016, 1, 1, 0, 0, 0, 0), "-838:59:59.000000"},
+	}
+
+	for i, t := range cases {
+		result, err := TimeDiff(t.T1, t.T2)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		str, err := result.Format("%H:%i:%s.%f")
+		c.Assert(err, IsNil)
+		c.Assert(str, Equals, t.Expect, Commentf("%d failed.", i))
+	}
+
+	_, err := TimeDiff(newMysqlTime(2016, 1, 1, 0, 0, 0, 0), newMysqlTime(0, 0, 0, 12, 0, 0, 0))
+	c.Assert(err, NotNil)
+	_, err = TimeDiff(newMysqlTime(0, 0, 0, 12, 0, 0, 0), newMysqlTime(2016, 1, 1, 0, 0, 0, 0))
+	c.Assert(err, NotNil)
+}
+
+func (s *testMyTimeSuite) TestAddTime(c *C) {
+	cases := []struct {
+		T   mysqlTime
+		D   mysqlTime
+		Add string
+		Sub string
+	}{
+		// Crossing midnight.
+		{newMysqlTime(2016, 6, 15, 23, 0, 0, 0), newMysqlTime(0, 0, 0, 1, 30, 0, 0), "2016-06-16 00:30:00.000000", "2016-06-15 21:30:00.000000"},
+		// Crossing a month end and a year end.
+		{newMysqlTime(2016, 2, 29, 22, 0, 0, 0), newMysqlTime(0, 0, 0, 2, 0, 0, 0), "2016-03-01 00:00:00.000000", "2016-02-29 20:00:00.000000"},
+		{newMysqlTime(2007, 12, 31, 23, 59, 59, 999999), newMysqlTime(0, 0, 0, 1, 1, 1, 2), "2008-01-01 01:01:01.000001", "2007-12-31 22:58:58.999997"},
+		// Subtracting crosses the month start.
+		{newMysqlTime(2016, 3, 1, 0, 0, 0, 0), newMysqlTime(0, 0, 0, 0, 0, 0, 1), "2016-03-01 00:00:00.000001", "2016-02-29 23:59:59.999999"},
+		// Hours beyond a day.
+		{newMysqlTime(2016, 1, 31, 12, 0, 0, 0), newMysqlTime(0, 0, 0, 50, 0, 0, 0), "2016-02-02 14:00:00.000000", "2016-01-29 10:00:00.000000"},
+		// TIME values.
+		{newMysqlTime(0, 0, 0, 1, 0, 0, 0), newMysqlTime(0, 0, 0, 2, 0, 0, 500000), "0000-00-00 03:00:00.500000", "0000-00-00 -01:00:00.500000"},
+		{newMysqlTime(0, 0, 0, 800, 0, 0, 0), newMysqlTime(0, 0, 0, 100, 0, 0, 0), "0000-00-00 838:59:59.000000", "0000-00-00 700:00:00.000000"},
+	}
+
+	for i, t := range cases {
+		result, err := AddTime(t.T, t.D)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		str, err := result.Format("%Y-%m-%d %H:%i:%s.%f")
+		c.Assert(err, IsNil)
+		c.Assert(str, Equals, t.Add, Commentf("%d failed.", i))
+
+		result, err = SubTime(t.T, t.D)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		str, err = result.Format("%Y-%m-%d %H:%i:%s.%f")
+		c.Assert(err, IsNil)
+		c.Assert(str, Equals, t.Sub, Commentf("%d failed.", i))
+	}
+
+	// Adding a negative TIME value equals to subtracting its magnitude.
+	result, err := AddTime(newMysqlTime(2016, 1, 1, 0, 0, 0, 0), SecToTime(-1, -500000))
+	c.Assert(err, IsNil)
+	c.Assert(result, Equals, newMysqlTime(2015, 12, 31, 23, 59, 58, 500000))
+
+	_, err = AddTime(newMysqlTime(2016, 1, 1, 0, 0, 0, 0), newMysqlTime(2016, 1, 1, 1, 0, 0, 0))
+	c.Assert(err, NotNil)
+	_, err = AddTime(newMysqlTime(2016, 0, 1, 0, 0, 0, 0), newMysqlTime(0, 0, 0, 1, 0, 0, 0))
+	c.Assert(err, NotNil)
+	_, err = AddTime(newMysqlTime(9999, 12, 31, 23, 0, 0, 0), newMysqlTime(0, 0, 0, 1, 0, 0, 0))
+	c.Assert(err, NotNil)
+}
+
+func (s *testMyTimeSuite) TestUnixTimestamp(c *C) {
+	east8 := gotime.FixedZone("UTC+8", 8*3600)
+	cases := []struct {
+		T            mysqlTime
+		Loc          *gotime.Location
+		Seconds      int64
+		Microseconds int
+	}{
+		{newMysqlTime(1970, 1, 1, 0, 0, 0, 0), gotime.UTC, 0, 0},
+		{newMysqlTime(1970, 1, 1, 8, 0, 0, 0), east8, 0, 0},
+		{newMysqlTime(2015, 11, 13, 10, 20, 19, 12), gotime.UTC, 1447410019, 12},
+		{newMysqlTime(2015, 11, 13, 10, 20, 19, 12), east8, 1447381219, 12},
+		{newMysqlTime(2038, 1, 19, 3, 14, 7, 999999), gotime.UTC, 2147483647, 999999},
+	}
+
+	for i, t := range cases {
+		seconds, microseconds, err := UnixTimestamp(t.T, t.Loc)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(seconds, Equals, t.Seconds, Commentf("%d failed.", i))
+		c.Assert(microseconds, Equals, t.Microseconds, Commentf("%d failed.", i))
+	}
+
+	// 1970-01-01 00:00:00 in UTC+8 is before the epoch.
+	_, _, err := UnixTimestamp(newMysqlTime(1970, 1, 1, 0, 0, 0, 0), east8)
+	c.Assert(err, NotNil)
+	_, _, err = UnixTimestamp(newMysqlTime(1969, 12, 31, 23, 59, 59, 0), gotime.UTC)
+	c.Assert(err, NotNil)
+	_, _, err = UnixTimestamp(ZeroTime, gotime.UTC)
+	c.Assert(err, NotNil)
+	_, _, err = UnixTimestamp(newMysqlTime(2016, 0, 1, 0, 0, 0, 0), gotime.UTC)
+	c.Assert(err, NotNil)
+}
+
+func (s *testMyTimeSuite) TestFromUnixTime(c *C) {
+	east8 := gotime.FixedZone("UTC+8", 8*3600)
+	cases := []struct {
+		Seconds      int64
+		Microseconds int64
+		Loc          *gotime.Location
+		Expect       mysqlTime
+	}{
+		{0, 0, gotime.UTC, newMysqlTime(1970, 1, 1, 0, 0, 0, 0)},
+		{0, 0, east8, newMysqlTime(1970, 1, 1, 8, 0, 0, 0)},
+		{1447410019, 12, gotime.UTC, newMysqlTime(2015, 11, 13, 10, 20, 19, 12)},
+		{1447410019, 999999, east8, newMysqlTime(2015, 11, 13, 18, 20, 19, 999999)},
+	}
+
+	for i, t := range cases {
+		result, err := FromUnixTime(t.Seconds, t.Microseconds, t.Loc)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(result, Equals, t.Expect, Commentf("%d failed.", i))
+
+		seconds, microseconds, err := UnixTimestamp(result, t.Loc)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(seconds, Equals, t.Seconds, Commentf("%d failed.", i))
+		c.Assert(int64(microseconds), Equals, t.Microseconds, Commentf("%d failed.", i))
+	}
+
+	_, err := FromUnixTime(-1, 0, gotime.UTC)
+	c.Assert(err, NotNil)
+	_, err = FromUnixTime(0, -1, gotime.UTC)
+	c.Assert(err, NotNil)
+
+	result, err := FromUnixTime(253402300799, 999999, gotime.UTC)
+	c.Assert(err, IsNil)
+	c.Assert(result, Equals, newMysqlTime(9999, 12, 31, 23, 59, 59, 999999))
+	_, err = FromUnixTime(253402300799+1, 0, gotime.UTC)
+	c.Assert(terror.ErrorEqual(err, ErrDatetimeOutOfRange), IsTrue)
+	_, err = FromUnixTime(253402300799, 0, east8)
+	c.Assert(terror.ErrorEqual(err, ErrDatetimeOutOfRange), IsTrue)
+	for _, sec := range []int64{3e11, 1e13, math.MaxInt64} {
+		_, err = FromUnixTime(sec, 0, gotime.UTC)
+		c.Assert(terror.ErrorEqual(err, ErrDatetimeOutOfRange), IsTrue, Commentf("%d failed.", sec))
+	}
+}
+
+func (s *testMyTimeSuite) TestConvertTZ(c *C) {
+	ny, err := gotime.LoadLocation("America/New_York")
+	c.Assert(err, IsNil)
+	shanghai, err := gotime.LoadLocation("Asia/Shanghai")
+	c.Assert(err, IsNil)
+
+	cases := []struct {
+		T      mysqlTime
+		From   *gotime.Location
+		To     *gotime.Location
+		Expect mysqlTime
+	}{
+		{newMysqlTime(2004, 1, 1, 12, 0, 0, 0), gotime.UTC, shanghai, newMysqlTime(2004, 1, 1, 20, 0, 0, 0)},
+		{newMysqlTime(2004, 1, 1, 12, 0, 0, 123), shanghai, ny, newMysqlTime(2003, 12, 31, 23, 0, 0, 123)},
+		{newMysqlTime(2016, 7, 1, 0, 0, 0, 0), ny, gotime.UTC, newMysqlTime(2016, 7, 1, 4, 0, 0, 0)},
+		// 2016-03-13 02:30:00 doesn't exist in New York, it's normalized by gotime.Date.
+		{newMysqlTime(2016, 3, 13, 2, 30, 0, 0), ny, gotime.UTC, newMysqlTime(2016, 3, 13, 6, 30, 0, 0)},
+		{newMysqlTime(2016, 3, 13, 3, 30, 0, 0), ny, gotime.UTC, newMysqlTime(2016, 3, 13, 7, 30, 0, 0)},
+		// 2016-11-06 01:30:00 is ambiguous in New York, the first one is chosen by gotime.Date.
+		{newMysqlTime(2016, 11, 6, 1, 30, 0, 0), ny, gotime.UTC, newMysqlTime(2016, 11, 6, 5, 30, 0, 0)},
+		{newMysqlTime(2016, 11, 6, 5, 30, 0, 0), gotime.UTC, ny, newMysqlTime(2016, 11, 6, 1, 30, 0, 0)},
+		{newMysqlTime(2016, 11, 6, 6, 30, 0, 0), gotime.UTC, ny, newMysqlTime(2016, 11, 6, 1, 30, 0, 0)},
+	}
+
+	for i, t := range cases {
+		result, err := ConvertTZ(t.T, t.From, t.To)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(result, Equals, t.Expect, Commentf("%d failed.", i))
+	}
+
+	_, err = ConvertTZ(newMysqlTime(9999, 12, 31, 23, 0, 0, 0), gotime.UTC, shanghai)
+	c.Assert(err, NotNil)
+	_, err = ConvertTZ(newMysqlTime(2016, 0, 31, 23, 0, 0, 0), gotime.UTC, shanghai)
+	c.Assert(err, NotNil)
+	_, err = ConvertTZ(SecToTime(-3600, 0), gotime.UTC, shanghai)
+	c.Assert(err, NotNil)
+
+	// Invalid datetimes are rejected rather than normalized.
+	errTbl := []mysqlTime{
+		newMysqlTime(2016, 2, 30, 0, 0, 0, 0),
+		newMysqlTime(2015, 2, 29, 0, 0, 0, 0),
+		newMysqlTime(2016, 13, 1, 0, 0, 0, 0),
+		newMysqlTime(2016, 12, 31, 24, 0, 0, 0),
+		newMysqlTime(2016, 12, 31, 23, 60, 0, 0),
+		newMysqlTime(2016, 12, 31, 23, 59, 60, 0),
+		newMysqlTime(2016, 12, 31, 23, 59, 59, 1000000),
+		newMysqlTime(10000, 1, 1, 0, 0, 0, 0),
+	}
+	for i, t := range errTbl {
+		_, err = ConvertTZ(t, gotime.UTC, gotime.UTC)
+		c.Assert(terror.ErrorEqual(err, ErrInvalidTimeFormat), IsTrue, Commentf("%d failed.", i))
+	}
+}
+
+func (s *testMyTimeSuite) TestLastDay(c *C) {
+	expects := []int{31, 28, 31, 30, 31, 30, 31, 31, 30, 31, 30, 31}
+	for i, expect := range expects {
+		month := i + 1
+		result, err := LastDay(newMysqlTime(2015, month, 10, 12, 30, 0, 0))
+		c.Assert(err, IsNil)
+		c.Assert(result, Equals, newMysqlTime(2015, month, expect, 0, 0, 0, 0), Commentf("month %d failed.", month))
+	}
+
+	result, err := LastDay(newMysqlTime(2004, 2, 5, 0, 0, 0, 0))
+	c.Assert(err, IsNil)
+	c.Assert(result, Equals, newMysqlTime(2004, 2, 29, 0, 0, 0, 0))
+	result, err = LastDay(newMysqlTime(1900, 2, 5, 0, 0, 0, 0))
+	c.Assert(err, IsNil)
+	c.Assert(result, Equals, newMysqlTime(1900, 2, 28, 0, 0, 0, 0))
+
+	_, err = LastDay(newMysqlTime(2003, 3, 0, 0, 0, 0, 0))
+	c.Assert(err, NotNil)
+	_, err = LastDay(newMysqlTime(2003, 0, 1, 0, 0, 0, 0))
+	c.Assert(err, NotNil)
+}
+
+func (s *testMyTimeSuite) TestMakeDate(c *C) {
+	cases := []struct {
+		Year      int
+		DayOfYear int
+		Expect    mysqlTime
+	}{
+		{2011, 31, newMysqlTime(2011, 1, 31, 0, 0, 0, 0)},
+		{2011, 32, newMysqlTime(2011, 2, 1, 0, 0, 0, 0)},
+		{2011, 365, newMysqlTime(2011, 12, 31, 0, 0, 0, 0)},
+		{2011, 366, newMysqlTime(2012, 1, 1, 0, 0, 0, 0)},
+		{2012, 366, newMysqlTime(2012, 12, 31, 0, 0, 0, 0)},
+		{2011, 365 + 366 + 1, newMysqlTime(2013, 1, 1, 0, 0, 0, 0)},
+		{16, 60, newMysqlTime(2016, 2, 29, 0, 0, 0, 0)},
+		{99, 1, newMysqlTime(1999, 1, 1, 0, 0, 0, 0)},
+		{9999, 365, newMysqlTime(9999, 12, 31, 0, 0, 0, 0)},
+	}
+
+	for i, t := range cases {
+		result, err := MakeDate(t.Year, t.DayOfYear)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(result, Equals, t.Expect, Commentf("%d failed.", i))
+	}
+
+	_, err := MakeDate(2011, 0)
+	c.Assert(err, NotNil)
+	_, err = MakeDate(-1, 1)
+	c.Assert(err, NotNil)
+	_, err = MakeDate(10000, 1)
+	c.Assert(err, NotNil)
+	_, err = MakeDate(9999, 366)
+	c.Assert(err, NotNil)
+}
+
+func (s *testMyTimeSuite) TestMakeTime(c *C) {
+	cases := []struct {
+		Hour     int
+		Minute   int
+		Second   int
+		Microsec int
+		Expect   string
+	}{
+		{12, 15, 30, 0, "12:15:30.000000"},
+		{12, 15, 30, 500000, "12:15:30.500000"},
+		{100, 0, 0, 0, "100:00:00.000000"},
+		{-2, 30, 0, 0, "-02:30:00.000000"},
+		{838, 59, 59, 0, "838:59:59.000000"},
+		{839, 0, 0, 0, "838:59:59.000000"},
+		{-1000, 0, 0, 0, "-838:59:59.000000"},
+	}
+
+	for i, t := range cases {
+		result, err := MakeTime(t.Hour, t.Minute, t.Second, t.Microsec)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		str, err := result.Format("%H:%i:%s.%f")
+		c.Assert(err, IsNil)
+		c.Assert(str, Equals, t.Expect, Commentf("%d failed.", i))
+	}
+
+	_, err := MakeTime(12, 60, 0, 0)
+	c.Assert(err, NotNil)
+	_, err = MakeTime(12, 0, -1, 0)
+	c.Assert(err, NotNil)
+	_, err = MakeTime(12, 0, 0, 1000000)
+	c.Assert(err, NotNil)
+}
+
+func (s *testMyTimeSuite) TestPeriod(c *C) {
+	addCases := []struct {
+		Period uint64
+		Months int
+		Expect uint64
+	}{
+		{200801, 2, 200803},
+		{801, 2, 200803},
+		{200811, 2, 200901},
+		{200812, 1, 200901},
+		{200801, -1, 200712},
+		{200801, -13, 200612},
+		{9912, 1, 200001},
+		{6912, 1, 207001},
+		{7001, -1, 196912},
+		{200801, 0, 200801},
+		{0, 2, 0},
+	}
+
+	for i, t := range addCases {
+		c.Assert(PeriodAdd(t.Period, t.Months), Equals, t.Expect, Commentf("%d failed.", i))
+	}
+
+	diffCases := []struct {
+		P1     uint64
+		P2     uint64
+		Expect int64
+	}{
+		{200802, 200703, 11},
+		{200703, 200802, -11},
+		{802, 703, 11},
+		{200802, 703, 11},
+		{7001, 6912, -1199},
+		{201601, 201512, 1},
+	}
+
+	for i, t := range diffCases {
+		c.Assert(PeriodDiff(t.P1, t.P2), Equals, t.Expect, Commentf("%d failed.", i))
+	}
+}
+
+func (s *testMyTimeSuite) TestCompare(c *C) {
+	cases := []struct {
+		T1     mysqlTime
+		T2     mysqlTime
+		Expect int
+	}{
+		{newMysqlTime(2016, 12, 31, 23, 59, 59, 1), newMysqlTime(2016, 12, 31, 23, 59, 59, 2), -1},
+		{newMysqlTime(2016, 12, 31, 23, 59, 59, 999999), newMysqlTime(2017, 1, 1, 0, 0, 0, 0), -1},
+		{newMysqlTime(2016, 12, 31, 23, 59, 59, 5), newMysqlTime(2016, 12, 31, 23, 59, 59, 5), 0},
+		{newMysqlTime(2016, 2, 1, 0, 0, 0, 0), newMysqlTime(2016, 1, 31, 23, 59, 59, 999999), 1},
+		{ZeroTime, newMysqlTime(1, 1, 1, 0, 0, 0, 0), -1},
+		{ZeroTime, newMysqlTime(0, 0, 0, 0, 0, 0, 1), -1},
+		{ZeroTime, ZeroTime, 0},
+	}
+
+	for i, t := range cases {
+		c.Assert(Compare(t.T1, t.T2), Equals, t.Expect, Commentf("%d failed.", i))
+		c.Assert(Compare(t.T2, t.T1), Equals, -t.Expect, Commentf("%d failed.", i))
+	}
+}
+
+func (s *testMyTimeSuite) TestMarshalBinary(c *C) {
+	// Values are in ascending order.
+	values := []mysqlTime{
+		SecToTime(-3020399, 0),
+		SecToTime(-3600, -1),
+		SecToTime(-3600, 0),
+		SecToTime(-1, 0),
+		SecToTime(0, -1),
+		ZeroTime,
+		newMysqlTime(0, 0, 0, 0, 0, 0, 1),
+		newMysqlTime(0, 0, 0, 838, 59, 59, 0),
+		newMysqlTime(1, 1, 1, 0, 0, 0, 0),
+		newMysqlTime(2016, 0, 0, 0, 0, 0, 0),
+		newMysqlTime(2016, 12, 31, 23, 59, 59, 999998),
+		newMysqlTime(2016, 12, 31, 23, 59, 59, 999999),
+		newMysqlTime(2017, 1, 1, 0, 0, 0, 0),
+		newMysqlTime(9999, 12, 31, 23, 59, 59, 999999),
+	}
+
+	encoded := make([][]byte, 0, len(values))
+	for i, t := range values {
+		data, err := t.MarshalBinary()
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		var result mysqlTime
+		err = result.UnmarshalBinary(data)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(result, Equals, t, Commentf("%d failed.", i))
+		encoded = append(encoded, data)
+	}
+
+	for i := range values {
+		for j := range values {
+			c.Assert(bytes.Compare(encoded[i], encoded[j]), Equals, Compare(values[i], values[j]), Commentf("%d %d failed.", i, j))
+		}
+	}
+
+	// fsp is kept, so is the String.
+	fspValues := []mysqlTime{
+		newMysqlTime(0, 0, 0, 10, 0, 0, 0).withFsp(2),
+		SecToTime(-3600, 0).withFsp(3),
+		newMysqlTime(2016, 12, 31, 23, 59, 59, 120000).withFsp(6),
+	}
+	for i, t := range fspValues {
+		data, err := t.MarshalBinary()
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		var result mysqlTime
+		c.Assert(result.UnmarshalBinary(data), IsNil, Commentf("%d failed.", i))
+		c.Assert(result, Equals, t, Commentf("%d failed.", i))
+		c.Assert(result.String(), Equals, t.String(), Commentf("%d failed.", i))
+	}
+
+	// The encoding without the fsp byte is decoded with fsp 0.
+	data, err := newMysqlTime(2016, 12, 31, 23, 59, 59, 120000).withFsp(6).MarshalBinary()
+	c.Assert(err, IsNil)
+	var result mysqlTime
+	c.Assert(result.UnmarshalBinary(data[:mysqlTimeBinaryLenV1]), IsNil)
+	c.Assert(result, Equals, newMysqlTime(2016, 12, 31, 23, 59, 59, 120000))
+	data[mysqlTimeBinaryLen-1] = byte(MaxFsp + 1)
+	c.Assert(terror.ErrorEqual(result.UnmarshalBinary(data), ErrInvalidTimeFormat), IsTrue)
+
+	c.Assert(result.UnmarshalBinary([]byte{1, 2, 3}), NotNil)
+	c.Assert(result.UnmarshalBinary(make([]byte, mysqlTimeBinaryLen+1)), NotNil)
+	_, err = mysqlTime{hour: 0x10000}.MarshalBinary()
+	c.Assert(err, NotNil)
+
+	// The fields out of range are rejected, the data may be corrupted in storage.
+	errValues := []mysqlTime{
+		newMysqlTime(2016, 200, 99, 30, 70, 70, 0),
+		newMysqlTime(2016, 13, 1, 0, 0, 0, 0),
+		newMysqlTime(2016, 2, 30, 0, 0, 0, 0),
+		newMysqlTime(2016, 12, 31, 24, 0, 0, 0),
+		newMysqlTime(2016, 12, 31, 0, 60, 0, 0),
+		newMysqlTime(2016, 12, 31, 0, 0, 60, 0),
+		newMysqlTime(2016, 12, 31, 0, 0, 0, 1000000),
+		newMysqlTime(10000, 1, 1, 0, 0, 0, 0),
+		newMysqlTime(0, 0, 0, 839, 0, 0, 0),
+		{year: 2016, month: 12, day: 31, neg: true},
+	}
+	for i, t := range errValues {
+		data, err := t.MarshalBinary()
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		result = newMysqlTime(2017, 1, 1, 0, 0, 0, 0)
+		err = result.UnmarshalBinary(data)
+		c.Assert(terror.ErrorEqual(err, ErrInvalidTimeFormat), IsTrue, Commentf("%d failed.", i))
+		// The receiver is unchanged on error.
+		c.Assert(result, Equals, newMysqlTime(2017, 1, 1, 0, 0, 0, 0), Commentf("%d failed.", i))
+	}
+}
+
+func (s *testMyTimeSuite) TestMarshalJSON(c *C) {
+	tbl := []struct {
+		t    mysqlTime
+		json string
+	}{
+		{newMysqlTime(2016, 1, 31, 12, 30, 59, 123456), `"2016-01-31 12:30:59.123456"`},
+		{newMysqlTime(1, 1, 1, 0, 0, 0, 0), `"0001-01-01 00:00:00"`},
+		{newMysqlTime(9999, 12, 31, 23, 59, 59, 999999), `"9999-12-31 23:59:59.999999"`},
+		{ZeroTime, `"0000-00-00 00:00:00"`},
+	}
+
+	for i, t := range tbl {
+		data, err := json.Marshal(t.t)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(string(data), Equals, t.json, Commentf("%d failed.", i))
+		var result mysqlTime
+		err = json.Unmarshal(data, &result)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(result, Equals, t.t, Commentf("%d failed.", i))
+	}
+
+	errTbl := []string{
+		`"2016-02-30 00:00:00"`,
+		`"2016-13-01 00:00:00"`,
+		`"2016-00-01 00:00:00"`,
+		`"abc"`,
+		`123`,
+	}
+	for i, t := range errTbl {
+		var result mysqlTime
+		err := json.Unmarshal([]byte(t), &result)
+		c.Assert(err, NotNil, Commentf("%d failed.", i))
+	}
+
+	// The values MySQL stores but gotime.Time can't represent are encoded too.
+	for i, t := range []struct {
+		t    mysqlTime
+		json string
+	}{
+		{newMysqlTime(2016, 0, 0, 0, 0, 0, 0), `"2016-00-00 00:00:00"`},
+		{newMysqlTime(0, 0, 0, 30, 0, 0, 0), `"0000-00-00 30:00:00"`},
+		{SecToTime(-3600, 0), `"0000-00-00 -01:00:00"`},
+		{newMysqlTime(2016, 1, 31, 10, 0, 0, 0).withFsp(2), `"2016-01-31 10:00:00.00"`},
+	} {
+		data, err := json.Marshal(t.t)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(string(data), Equals, t.json, Commentf("%d failed.", i))
+	}
+
+	_, err := json.Marshal(newMysqlTime(2016, 2, 30, 0, 0, 0, 0))
+	c.Assert(err, NotNil)
+	_, err = json.Marshal(newMysqlTime(0, 0, 0, 839, 0, 0, 0))
+	c.Assert(err, NotNil)
+}
+
+func (s *testMyTimeSuite) TestRoundToFsp(c *C) {
+	tbl := []struct {
+		t      mysqlTime
+		fsp    int
+		expect mysqlTime
+	}{
+		{newMysqlTime(2016, 12, 31, 23, 59, 59, 999999), 0, newMysqlTime(2017, 1, 1, 0, 0, 0, 0)},
+		{newMysqlTime(2016, 12, 31, 23, 59, 59, 999999), 5, newMysqlTime(2017, 1, 1, 0, 0, 0, 0).withFsp(5)},
+		{newMysqlTime(2016, 12, 31, 23, 59, 59, 999999), 6, newMysqlTime(2016, 12, 31, 23, 59, 59, 999999).withFsp(6)},
+		{newMysqlTime(2016, 2, 28, 23, 59, 59, 500000), 0, newMysqlTime(2016, 2, 29, 0, 0, 0, 0)},
+		{newMysqlTime(2015, 2, 28, 23, 59, 59, 500000), 0, newMysqlTime(2015, 3, 1, 0, 0, 0, 0)},
+		{newMysqlTime(2016, 1, 1, 10, 59, 59, 499999), 0, newMysqlTime(2016, 1, 1, 10, 59, 59, 0)},
+		{newMysqlTime(2016, 1, 1, 10, 30, 0, 123456), 3, newMysqlTime(2016, 1, 1, 10, 30, 0, 123000).withFsp(3)},
+		{newMysqlTime(2016, 1, 1, 10, 30, 0, 123456), 4, newMysqlTime(2016, 1, 1, 10, 30, 0, 123500).withFsp(4)},
+		{newMysqlTime(2016, 1, 1, 10, 30, 0, 123456), UnspecifiedFsp, newMysqlTime(2016, 1, 1, 10, 30, 0, 0)},
+		{newMysqlTime(0, 0, 0, 100, 59, 59, 999999), 0, newMysqlTime(0, 0, 0, 101, 0, 0, 0)},
+		{SecToTime(-59, -500000), 0, SecToTime(-60, 0)},
+	}
+
+	for i, t := range tbl {
+		result, err := t.t.RoundToFsp(t.fsp)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(result, Equals, t.expect, Commentf("%d failed.", i))
+	}
+
+	errTbl := []struct {
+		t   mysqlTime
+		fsp int
+	}{
+		{newMysqlTime(9999, 12, 31, 23, 59, 59, 999999), 0},
+		{newMysqlTime(0, 0, 0, 838, 59, 59, 999999), 0},
+		{newMysqlTime(2016, 0, 0, 23, 59, 59, 999999), 0},
+		{newMysqlTime(2016, 1, 1, 0, 0, 0, 0), 7},
+	}
+	for i, t := range errTbl {
+		_, err := t.t.RoundToFsp(t.fsp)
+		c.Assert(err, NotNil, Commentf("%d failed.", i))
+	}
+}
+
+func (s *testMyTimeSuite) TestTruncateToFsp(c *C) {
+	t := newMysqlTime(2016, 12, 31, 23, 59, 59, 999999)
+	expects := []int{0, 900000, 990000, 999000, 999900, 999990, 999999}
+	for fsp, expect := range expects {
+		c.Assert(t.TruncateToFsp(fsp), Equals, newMysqlTime(2016, 12, 31, 23, 59, 59, expect).withFsp(fsp), Commentf("%d failed.", fsp))
+	}
+
+	t = newMysqlTime(2016, 1, 1, 10, 30, 0, 123456)
+	expects = []int{0, 100000, 120000, 123000, 123400, 123450, 123456}
+	for fsp, expect := range expects {
+		c.Assert(t.TruncateToFsp(fsp), Equals, newMysqlTime(2016, 1, 1, 10, 30, 0, expect).withFsp(fsp), Commentf("%d failed.", fsp))
+	}
+
+	c.Assert(t.TruncateToFsp(UnspecifiedFsp), Equals, newMysqlTime(2016, 1, 1, 10, 30, 0, 0))
+	c.Assert(t.TruncateToFsp(7), Equals, newMysqlTime(2016, 1, 1, 10, 30, 0, 0))
+	c.Assert(SecToTime(-1, -654321).TruncateToFsp(2), Equals, SecToTime(-1, -650000).withFsp(2))
+}
+
+func (s *testMyTimeSuite) TestIsZero(c *C) {
+	tbl := []struct {
+		t          mysqlTime
+		isZero     bool
+		isZeroDate bool
+	}{
+		{ZeroTime, true, true},
+		{newMysqlTime(0, 0, 0, 0, 0, 0, 0), true, true},
+		{newMysqlTime(0, 0, 0, 10, 0, 0, 0), false, true},
+		{newMysqlTime(0, 0, 0, 0, 0, 0, 1), false, true},
+		{SecToTime(-1, 0), false, true},
+		{newMysqlTime(0, 1, 0, 0, 0, 0, 0), false, false},
+		{newMysqlTime(2016, 0, 0, 0, 0, 0, 0), false, false},
+		{newMysqlTime(2016, 12, 31, 0, 0, 0, 0), false, false},
+	}
+
+	for i, t := range tbl {
+		c.Assert(t.t.IsZero(), Equals, t.isZero, Commentf("%d failed.", i))
+		c.Assert(t.t.IsZeroDate(), Equals, t.isZeroDate, Commentf("%d failed.", i))
+	}
+}
+
+func (s *testMyTimeSuite) TestValidate(c *C) {
+	tbl := []struct {
+		t mysqlTime
+		// Whether t is valid for the flags (noZeroDate, noZeroInDate) of
+		// (false, false), (true, false), (false, true), (true, true).
+		valid [4]bool
+	}{
+		{ZeroTime, [4]bool{true, false, true, false}},
+		{newMysqlTime(0, 0, 0, 10, 0, 0, 0), [4]bool{true, false, true, false}},
+		{newMysqlTime(2016, 0, 0, 0, 0, 0, 0), [4]bool{true, true, false, false}},
+		{newMysqlTime(2016, 0, 10, 0, 0, 0, 0), [4]bool{true, true, false, false}},
+		{newMysqlTime(2016, 10, 0, 0, 0, 0, 0), [4]bool{true, true, false, false}},
+		{newMysqlTime(0, 10, 10, 0, 0, 0, 0), [4]bool{true, true, true, true}},
+		{newMysqlTime(2016, 10, 10, 12, 30, 0, 0), [4]bool{true, true, true, true}},
+	}
+
+	for i, t := range tbl {
+		for j, valid := range t.valid {
+			err := t.t.Validate(j&1 != 0, j&2 != 0)
+			if valid {
+				c.Assert(err, IsNil, Commentf("%d %d failed.", i, j))
+			} else {
+				c.Assert(err, NotNil, Commentf("%d %d failed.", i, j))
+			}
+		}
+	}
+}
+
+func (s *testMyTimeSuite) TestString(c *C) {
+	tbl := []struct {
+		t      mysqlTime
+		expect string
+	}{
+		{ZeroTime, "0000-00-00 00:00:00"},
+		{newMysqlTime(2016, 12, 31, 23, 59, 59, 0), "2016-12-31 23:59:59"},
+		{newMysqlTime(2016, 1, 2, 3, 4, 5, 6), "2016-01-02 03:04:05.000006"},
+		{newMysqlTime(2016, 1, 2, 3, 4, 5, 120000), "2016-01-02 03:04:05.120000"},
+		{newMysqlTime(2016, 0, 0, 0, 0, 0, 0), "2016-00-00 00:00:00"},
+		{newMysqlTime(1, 1, 1, 0, 0, 0, 0), "0001-01-01 00:00:00"},
+		// Trailing zeros are kept with fsp.
+		{newMysqlTime(2016, 1, 2, 10, 0, 0, 0).withFsp(2), "2016-01-02 10:00:00.00"},
+		{newMysqlTime(2016, 1, 2, 3, 4, 5, 120000).withFsp(3), "2016-01-02 03:04:05.120"},
+		{newMysqlTime(0, 0, 0, 10, 0, 0, 0).withFsp(6), "0000-00-00 10:00:00.000000"},
+		// Invalid fsp is clamped.
+		{newMysqlTime(2016, 1, 2, 3, 4, 5, 6).withFsp(7), "2016-01-02 03:04:05.000006"},
+		{newMysqlTime(2016, 1, 2, 3, 4, 5, 6).withFsp(200), "2016-01-02 03:04:05.000006"},
+		{newMysqlTime(2016, 1, 2, 3, 4, 5, 0).withFsp(-1), "2016-01-02 03:04:05"},
+		// The sign of a negative TIME value is before the time part.
+		{SecToTime(-45000, 0), "0000-00-00 -12:30:00"},
+		{SecToTime(0, -500000), "0000-00-00 -00:00:00.500000"},
+	}
+
+	for i, t := range tbl {
+		c.Assert(t.t.String(), Equals, t.expect, Commentf("%d failed.", i))
+		c.Assert(fmt.Sprint(t.t), Equals, t.expect, Commentf("%d failed.", i))
+	}
+
+	v, err := ParseDatetimeLiteral("2016-12-31 10:00:00.00")
+	c.Assert(err, IsNil)
+	c.Assert(v.String(), Equals, "2016-12-31 10:00:00.00")
+	v, err = newMysqlTime(2016, 12, 31, 10, 0, 0, 4999).RoundToFsp(2)
+	c.Assert(err, IsNil)
+	c.Assert(v.String(), Equals, "2016-12-31 10:00:00.00")
+	c.Assert(v.Equal(newMysqlTime(2016, 12, 31, 10, 0, 0, 0)), IsTrue)
+	c.Assert(v.TruncateToFsp(0).String(), Equals, "2016-12-31 10:00:00")
+
+	// A fraction longer than 127 digits must not wrap the fsp to negative.
+	for _, n := range []int{7, 128, 200, 300} {
+		str := "2016-12-31 10:00:00." + strings.Repeat("1", n)
+		v, err = ParseDatetimeLiteral(str)
+		c.Assert(err, IsNil, Commentf("%d failed.", n))
+		c.Assert(v.String(), Equals, "2016-12-31 10:00:00.111111", Commentf("%d failed.", n))
+		var scanned mysqlTime
+		c.Assert(scanned.Scan(str), IsNil, Commentf("%d failed.", n))
+		c.Assert(scanned.String(), Equals, "2016-12-31 10:00:00.111111", Commentf("%d failed.", n))
+	}
+	data, err := newMysqlTime(2016, 12, 31, 10, 0, 0, 1).withFsp(6).MarshalBinary()
+	c.Assert(err, IsNil)
+	c.Assert(v.UnmarshalBinary(data), IsNil)
+	c.Assert(v.String(), Equals, "2016-12-31 10:00:00.000001")
+}
+
+func (s *testMyTimeSuite) TestParseDatetimeUint64(c *C) {
+	tbl := []struct {
+		n      uint64
+		expect mysqlTime
+	}{
+		{20161231235959, newMysqlTime(2016, 12, 31, 23, 59, 59, 0)},
+		{20160229000000, newMysqlTime(2016, 2, 29, 0, 0, 0, 0)},
+		{10000101000000, newMysqlTime(1000, 1, 1, 0, 0, 0, 0)},
+		{99991231235959, newMysqlTime(9999, 12, 31, 23, 59, 59, 0)},
+		{20160000000000, newMysqlTime(2016, 0, 0, 0, 0, 0, 0)},
+		{0, ZeroTime},
+	}
+
+	for i, t := range tbl {
+		result, err := ParseDatetimeUint64(t.n)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(result, Equals, t.expect, Commentf("%d failed.", i))
+		c.Assert(datetimeToUint64(result), Equals, t.n, Commentf("%d failed.", i))
+	}
+
+	// Two digit year is adjusted.
+	result, err := ParseDatetimeUint64(161231235959)
+	c.Assert(err, IsNil)
+	c.Assert(result, Equals, newMysqlTime(2016, 12, 31, 23, 59, 59, 0))
+	result, err = ParseDatetimeUint64(700101000000)
+	c.Assert(err, IsNil)
+	c.Assert(result, Equals, newMysqlTime(1970, 1, 1, 0, 0, 0, 0))
+
+	// Integer with 8 or less digits is a date like parseDateTimeFromNum.
+	dateTbl := []struct {
+		n      uint64
+		expect mysqlTime
+	}{
+		{161231, newMysqlTime(2016, 12, 31, 0, 0, 0, 0)},
+		{991231, newMysqlTime(1999, 12, 31, 0, 0, 0, 0)},
+		{20161231, newMysqlTime(2016, 12, 31, 0, 0, 0, 0)},
+		{99991231, newMysqlTime(9999, 12, 31, 0, 0, 0, 0)},
+	}
+	for i, t := range dateTbl {
+		result, err = ParseDatetimeUint64(t.n)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(result, Equals, t.expect, Commentf("%d failed.", i))
+		expect, err := ParseTimeFromInt64(int64(t.n))
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(Compare(result, expect.Time), Equals, 0, Commentf("%d failed.", i))
+	}
+
+	errTbl := []uint64{
+		235959,
+		100,
+		691232,
+		700000,
+		991232,
+		1000000,
+		1231231,
+		10000100,
+		125959,
+		99991232,
+		100000000,
+		101000000 - 1,
+		20161331000000,
+		20161232000000,
+		20150229000000,
+		19000229000000,
+		20161231240000,
+		20161231236000,
+		20161231235960,
+		100001231235959,
+	}
+	for i, n := range errTbl {
+		_, err := ParseDatetimeUint64(n)
+		c.Assert(err, NotNil, Commentf("%d failed.", i))
+	}
+}
+
+func (s *testMyTimeSuite) TestParseDateUint64(c *C) {
+	tbl := []struct {
+		n      uint64
+		expect mysqlTime
+	}{
+		{161231, newMysqlTime(2016, 12, 31, 0, 0, 0, 0)},
+		{691231, newMysqlTime(2069, 12, 31, 0, 0, 0, 0)},
+		{700101, newMysqlTime(1970, 1, 1, 0, 0, 0, 0)},
+		{991231, newMysqlTime(1999, 12, 31, 0, 0, 0, 0)},
+		{10101, newMysqlTime(2001, 1, 1, 0, 0, 0, 0)},
+		{20161231, newMysqlTime(2016, 12, 31, 0, 0, 0, 0)},
+		{19700101, newMysqlTime(1970, 1, 1, 0, 0, 0, 0)},
+		{1010101, newMysqlTime(101, 1, 1, 0, 0, 0, 0)},
+		{20160229, newMysqlTime(2016, 2, 29, 0, 0, 0, 0)},
+		{20160000, newMysqlTime(2016, 0, 0, 0, 0, 0, 0)},
+		{0, ZeroTime},
+	}
+
+	for i, t := range tbl {
+		result, err := ParseDateUint64(t.n)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(result, Equals, t.expect, Commentf("%d failed.", i))
+		if t.n >= 1000000 {
+			c.Assert(dateToUint64(result), Equals, t.n, Commentf("%d failed.", i))
+		}
+	}
+
+	errTbl := []uint64{
+		161331,
+		161232,
+		150229,
+		20161301,
+		20160230,
+		19000229,
+		100000101,
+	}
+	for i, n := range errTbl {
+		_, err := ParseDateUint64(n)
+		c.Assert(err, NotNil, Commentf("%d failed.", i))
+	}
+}
+
+func (s *testMyTimeSuite) TestEqual(c *C) {
+	tbl := []struct {
+		t1             mysqlTime
+		t2             TimeInternal
+		equal          bool
+		equalIgnoreFsp bool
+	}{
+		{newMysqlTime(2016, 12, 31, 23, 59, 59, 0), newMysqlTime(2016, 12, 31, 23, 59, 59, 0), true, true},
+		{newMysqlTime(2016, 12, 31, 23, 59, 59, 0), newMysqlTime(2016, 12, 31, 23, 59, 59, 123456), false, true},
+		{newMysqlTime(2016, 12, 31, 23, 59, 59, 999999), newMysqlTime(2016, 12, 31, 23, 59, 59, 1), false, true},
+		{newMysqlTime(2016, 12, 31, 23, 59, 59, 0), newMysqlTime(2016, 12, 31, 23, 59, 58, 0), false, false},
+		{newMysqlTime(2016, 12, 31, 0, 0, 0, 0), newMysqlTime(2016, 12, 30, 0, 0, 0, 0), false, false},
+		{ZeroTime, ZeroTime, true, true},
+		{SecToTime(-10, -500000), SecToTime(-10, -500000), true, true},
+		{SecToTime(-10, -500000), SecToTime(-10, 0), false, true},
+		{SecToTime(-10, 0), SecToTime(10, 0), false, false},
+	}
+
+	for i, t := range tbl {
+		c.Assert(t.t1.Equal(t.t2), Equals, t.equal, Commentf("%d failed.", i))
+		c.Assert(t.t1.EqualIgnoreFsp(t.t2), Equals, t.equalIgnoreFsp, Commentf("%d failed.", i))
+	}
+}
+
+func (s *testMyTimeSuite) TestDayInfo(c *C) {
+	// Check with calculating the day numbers by calcDaynr directly.
+	mismatch := 0
+	for daynr := minDaynr; daynr <= maxDaynr; daynr++ {
+		year, month, day := getDateFromDaynr(daynr)
+		info := newDayInfo(year, month, day)
+		firstDaynr := calcDaynr(year, 1, 1)
+		if info.daynr != daynr || info.firstDaynr != firstDaynr || info.yearDay != daynr-firstDaynr+1 {
+			mismatch++
+		}
+	}
+	c.Assert(mismatch, Equals, 0)
+
+	info := newDayInfo(2016, 0, 0)
+	c.Assert(info.firstDaynr, Equals, calcDaynr(2016, 1, 1))
+	c.Assert(info.daynr, Equals, calcDaynr(2016, 0, 0))
+}
+
+func (s *testMyTimeSuite) TestTimestampDiff(c *C) {
+	tbl := []struct {
+		unit   string
+		t1     mysqlTime
+		t2     mysqlTime
+		expect int64
+	}{
+		// Examples from MySQL document.
+		{"MONTH", newMysqlTime(2003, 2, 1, 0, 0, 0, 0), newMysqlTime(2003, 5, 1, 0, 0, 0, 0), 3},
+		{"YEAR", newMysqlTime(2002, 5, 1, 0, 0, 0, 0), newMysqlTime(2001, 1, 1, 0, 0, 0, 0), -1},
+		{"MINUTE", newMysqlTime(2003, 2, 1, 0, 0, 0, 0), newMysqlTime(2003, 5, 1, 12, 5, 55, 0), 128885},
+		{"MONTH", newMysqlTime(2016, 1, 31, 0, 0, 0, 0), newMysqlTime(2016, 2, 29, 0, 0, 0, 0), 0},
+		{"MONTH", newMysqlTime(2016, 1, 31, 12, 0, 0, 0), newMysqlTime(2016, 2, 29, 12, 0, 0, 0), 0},
+		{"MONTH", newMysqlTime(2016, 1, 29, 12, 0, 0, 1), newMysqlTime(2016, 2, 29, 12, 0, 0, 0), 0},
+		{"MONTH", newMysqlTime(2016, 1, 29, 12, 0, 0, 0), newMysqlTime(2016, 2, 29, 12, 0, 0, 0), 1},
+		{"QUARTER", newMysqlTime(2016, 1, 1, 0, 0, 0, 0), newMysqlTime(2016, 12, 31, 0, 0, 0, 0), 3},
+		{"quarter", newMysqlTime(2016, 12, 31, 0, 0, 0, 0), newMysqlTime(2016, 1, 1, 0, 0, 0, 0), -3},
+		{"WEEK", newMysqlTime(2016, 1, 1, 0, 0, 0, 0), newMysqlTime(2016, 1, 14, 23, 59, 59, 0), 1},
+		{"DAY", newMysqlTime(2016, 2, 28, 12, 0, 0, 0), newMysqlTime(2016, 3, 1, 11, 59, 59, 0), 1},
+		{"HOUR", newMysqlTime(2016, 12, 31, 23, 0, 0, 0), newMysqlTime(2017, 1, 1, 1, 30, 0, 0), 2},
+		{"SECOND", newMysqlTime(2016, 12, 31, 23, 59, 59, 0), newMysqlTime(2017, 1, 1, 0, 0, 0, 0), 1},
+		{"MICROSECOND", newMysqlTime(2016, 12, 31, 23, 59, 59, 999999), newMysqlTime(2017, 1, 1, 0, 0, 0, 0), 1},
+		// Dates decades apart don't overflow.
+		{"MICROSECOND", newMysqlTime(1970, 1, 1, 0, 0, 0, 0), newMysqlTime(2016, 12, 31, 23, 59, 59, 999999), 1483228799999999},
+		{"MICROSECOND", newMysqlTime(2000, 1, 1, 0, 0, 0, 1), newMysqlTime(1900, 1, 1, 0, 0, 0, 0), -3155673600000001},
+		{"MICROSECOND", newMysqlTime(1, 1, 1, 0, 0, 0, 0), newMysqlTime(9999, 12, 31, 23, 59, 59, 999999), 315537897599999999},
+		{"DAY", newMysqlTime(1900, 1, 1, 0, 0, 0, 0), newMysqlTime(2000, 1, 1, 0, 0, 0, 1), 36524},
+		{"YEAR", newMysqlTime(1, 1, 1, 0, 0, 0, 0), newMysqlTime(9999, 12, 31, 23, 59, 59, 999999), 9998},
+		{"UNKNOWN", newMysqlTime(1, 1, 1, 0, 0, 0, 0), newMysqlTime(9999, 12, 31, 23, 59, 59, 999999), 0},
+	}
+
+	for i, t := range tbl {
+		c.Assert(timestampDiff(t.unit, t.t1, t.t2), Equals, t.expect, Commentf("%d failed.", i))
+	}
+}
+
+func (s *testMyTimeSuite) TestParseDatetimeLiteral(c *C) {
+	tbl := []struct {
+		input  string
+		expect mysqlTime
+	}{
+		{"2016-12-31 23:59:59", newMysqlTime(2016, 12, 31, 23, 59, 59, 0)},
+		{"2016/12/31 23.59.59", newMysqlTime(2016, 12, 31, 23, 59, 59, 0)},
+		{"2016.12.31 23:59:59", newMysqlTime(2016, 12, 31, 23, 59, 59, 0)},
+		{"2016-12-31 23-59-59", newMysqlTime(2016, 12, 31, 23, 59, 59, 0)},
+		{"2016-12-31T23:59:59", newMysqlTime(2016, 12, 31, 23, 59, 59, 0)},
+		{"2016-12-31 23:59:59.5", newMysqlTime(2016, 12, 31, 23, 59, 59, 500000).withFsp(1)},
+		{"2016-12-31 23:59:59.000001", newMysqlTime(2016, 12, 31, 23, 59, 59, 1).withFsp(6)},
+		{"2016-12-31 23:59:59.1234564", newMysqlTime(2016, 12, 31, 23, 59, 59, 123456).withFsp(6)},
+		{"2016-12-31 23:59", newMysqlTime(2016, 12, 31, 23, 59, 0, 0)},
+		{"2016-12-31 23", newMysqlTime(2016, 12, 31, 23, 0, 0, 0)},
+		{"2016-12-31", newMysqlTime(2016, 12, 31, 0, 0, 0, 0)},
+		{"2016.12.31", newMysqlTime(2016, 12, 31, 0, 0, 0, 0)},
+		{"16-12-31", newMysqlTime(2016, 12, 31, 0, 0, 0, 0)},
+		{"99-12-31", newMysqlTime(1999, 12, 31, 0, 0, 0, 0)},
+		{"2016-1-2 3:4:5", newMysqlTime(2016, 1, 2, 3, 4, 5, 0)},
+		{"20161231235959", newMysqlTime(2016, 12, 31, 23, 59, 59, 0)},
+		{"20161231235959.25", newMysqlTime(2016, 12, 31, 23, 59, 59, 250000).withFsp(2)},
+		{"161231235959", newMysqlTime(2016, 12, 31, 23, 59, 59, 0)},
+		{"20161231", newMysqlTime(2016, 12, 31, 0, 0, 0, 0)},
+		{"161231", newMysqlTime(2016, 12, 31, 0, 0, 0, 0)},
+		{"2016-02-29 00:00:00", newMysqlTime(2016, 2, 29, 0, 0, 0, 0)},
+		{"0000-00-00 00:00:00", ZeroTime},
+	}
+
+	for i, t := range tbl {
+		v, err := ParseDatetimeLiteral(t.input)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(v, Equals, t.expect, Commentf("%d failed.", i))
+	}
+
+	errTbl := []string{
+		"",
+		"abc",
+		"2016-12-31 23:59:59T",
+		"2016-12-31  23:59:59",
+		"2016-12-31 24:00:00",
+		"2016-12-31 23:60:00",
+		"2016-12-31 23:59:60",
+		"2016-02-30",
+		"2015-02-29",
+		"2016-13-01",
+		"2016-12-32",
+		"2016-12",
+		"1612311",
+		"2016123123595",
+		"2016-12-31 23:59:59.123.4",
+		"2016-12-31 23:59:59.12a",
+	}
+
+	for i, t := range errTbl {
+		_, err := ParseDatetimeLiteral(t)
+		c.Assert(terror.ErrorEqual(err, ErrInvalidTimeFormat), IsTrue, Commentf("%d failed.", i))
+	}
+}
+
+func (s *testMyTimeSuite) TestParseDatetimeLiteralWithMode(c *C) {
+	tbl := []struct {
+		input    string
+		strict   bool
+		expect   mysqlTime
+		warnings int
+		hasErr   bool
+	}{
+		{"2016-02-30", true, ZeroTime, 0, true},
+		{"2016-02-30", false, ZeroTime, 1, false},
+		{"2016-12-31 24:00:00", true, ZeroTime, 0, true},
+		{"2016-12-31 24:00:00", false, ZeroTime, 1, false},
+		{"2016-02-29", true, newMysqlTime(2016, 2, 29, 0, 0, 0, 0), 0, false},
+		{"2016-02-29", false, newMysqlTime(2016, 2, 29, 0, 0, 0, 0), 0, false},
+		{"abc", true, ZeroTime, 0, true},
+		{"abc", false, ZeroTime, 0, true},
+		// Leap second.
+		{"2016-12-31 23:59:60", true, ZeroTime, 0, true},
+		{"2016-12-31 23:59:60", false, newMysqlTime(2016, 12, 31, 23, 59, 59, 0), 1, false},
+		{"2016-12-31 23:59:60.5", false, newMysqlTime(2016, 12, 31, 23, 59, 59, 500000).withFsp(1), 1, false},
+		{"2016-12-31 23:59:61", false, ZeroTime, 1, false},
+	}
+
+	for i, t := range tbl {
+		v, warnings, err := ParseDatetimeLiteralWithMode(t.input, t.strict)
+		if t.hasErr {
+			c.Assert(terror.ErrorEqual(err, ErrInvalidTimeFormat), IsTrue, Commentf("%d failed.", i))
+		} else {
+			c.Assert(err, IsNil, Commentf("%d failed.", i))
+		}
+		c.Assert(v, Equals, t.expect, Commentf("%d failed.", i))
+		c.Assert(warnings, HasLen, t.warnings, Commentf("%d failed.", i))
+		for _, w := range warnings {
+			if v.second == 59 {
+				c.Assert(terror.ErrorEqual(w, ErrTruncated), IsTrue, Commentf("%d failed.", i))
+			} else {
+				c.Assert(terror.ErrorEqual(w, ErrInvalidTimeFormat), IsTrue, Commentf("%d failed.", i))
+			}
+		}
+	}
+}
+
+func (s *testMyTimeSuite) TestParseDatetimeLiteralFsp(c *C) {
+	tbl := []struct {
+		input       string
+		microsecond int
+		fsp         int
+		warnings    int
+	}{
+		{"2016-12-31 10:00:00", 0, 0, 0},
+		{"2016-12-31 10:00:00.5", 500000, 1, 0},
+		{"2016-12-31 10:00:00.05", 50000, 2, 0},
+		{"2016-12-31 10:00:00.0001", 100, 4, 0},
+		{"2016-12-31 10:00:00.000001", 1, 6, 0},
+		{"2016-12-31 10:00:00.1234567", 123457, 6, 1},
+		{"20161231100000.05", 50000, 2, 0},
+		{"2016-12-31", 0, 0, 0},
+	}
+
+	for i, t := range tbl {
+		v, fsp, warnings, err := ParseDatetimeLiteralFsp(t.input)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(v.Microsecond(), Equals, t.microsecond, Commentf("%d failed.", i))
+		c.Assert(fsp, Equals, t.fsp, Commentf("%d failed.", i))
+		c.Assert(warnings, HasLen, t.warnings, Commentf("%d failed.", i))
+	}
+
+	_, _, _, err := ParseDatetimeLiteralFsp("2016-12-31 10:00:00.5x")
+	c.Assert(err, NotNil)
+}
+
+func (s *testMyTimeSuite) TestBeforeAfter(c *C) {
+	tbl := []struct {
+		t1     mysqlTime
+		t2     mysqlTime
+		before bool
+		after  bool
+	}{
+		{newMysqlTime(2016, 12, 31, 23, 59, 59, 0), newMysqlTime(2017, 1, 1, 0, 0, 0, 0), true, false},
+		{newMysqlTime(2017, 1, 1, 0, 0, 0, 0), newMysqlTime(2016, 12, 31, 23, 59, 59, 0), false, true},
+		{newMysqlTime(2016, 12, 31, 23, 59, 59, 1), newMysqlTime(2016, 12, 31, 23, 59, 59, 0), false, true},
+		{newMysqlTime(2016, 12, 31, 0, 0, 0, 0), newMysqlTime(2016, 12, 31, 0, 0, 0, 0), false, false},
+		{ZeroTime, newMysqlTime(1, 1, 1, 0, 0, 0, 0), true, false},
+		{ZeroTime, ZeroTime, false, false},
+	}
+
+	for i, t := range tbl {
+		c.Assert(t.t1.Before(t.t2), Equals, t.before, Commentf("%d failed.", i))
+		c.Assert(t.t1.After(t.t2), Equals, t.after, Commentf("%d failed.", i))
+		c.Assert(Compare(t.t1, t.t2) < 0, Equals, t.before, Commentf("%d failed.", i))
+	}
+}
+
+func (s *testMyTimeSuite) TestWithHelpers(c *C) {
+	t := newMysqlTime(2016, 12, 31, 23, 59, 59, 999999)
+	orig := t
+
+	c.Assert(t.withYear(2017), Equals, newMysqlTime(2017, 12, 31, 23, 59, 59, 999999))
+	c.Assert(t.withMonth(1), Equals, newMysqlTime(2016, 1, 31, 23, 59, 59, 999999))
+	c.Assert(t.withDay(1), Equals, newMysqlTime(2016, 12, 1, 23, 59, 59, 999999))
+	c.Assert(t.withHour(0), Equals, newMysqlTime(2016, 12, 31, 0, 59, 59, 999999))
+	c.Assert(t.withMinute(0), Equals, newMysqlTime(2016, 12, 31, 23, 0, 59, 999999))
+	c.Assert(t.withSecond(0), Equals, newMysqlTime(2016, 12, 31, 23, 59, 0, 999999))
+	c.Assert(t.withMicrosecond(0), Equals, newMysqlTime(2016, 12, 31, 23, 59, 59, 0))
+	c.Assert(t.withMonth(2).withDay(29), Equals, newMysqlTime(2016, 2, 29, 23, 59, 59, 999999))
+	c.Assert(t, Equals, orig)
+
+	neg := newMysqlTime(0, 0, 0, 12, 0, 0, 0)
+	neg.neg = true
+	c.Assert(neg.withMinute(30).IsNegative(), IsTrue)
+}
+
+func (s *testMyTimeSuite) TestDayOfYear(c *C) {
+	tbl := []struct {
+		t      mysqlTime
+		expect int
+	}{
+		{newMysqlTime(2016, 12, 31, 0, 0, 0, 0), 366},
+		{newMysqlTime(2015, 12, 31, 0, 0, 0, 0), 365},
+		{newMysqlTime(2016, 1, 1, 0, 0, 0, 0), 1},
+		{newMysqlTime(2016, 3, 1, 0, 0, 0, 0), 61},
+		{newMysqlTime(2015, 3, 1, 0, 0, 0, 0), 60},
+	}
+
+	for i, t := range tbl {
+		v, err := t.t.DayOfYear()
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(v, Equals, t.expect, Commentf("%d failed.", i))
+		c.Assert(t.t.YearDay(), Equals, v, Commentf("%d failed.", i))
+	}
+
+	for i, t := range []mysqlTime{ZeroTime, newMysqlTime(2016, 0, 10, 0, 0, 0, 0), newMysqlTime(2016, 10, 0, 0, 0, 0, 0)} {
+		_, err := t.DayOfYear()
+		c.Assert(terror.ErrorEqual(err, ErrInvalidTimeFormat), IsTrue, Commentf("%d failed.", i))
+		c.Assert(t.YearDay(), Equals, 0, Commentf("%d failed.", i))
+	}
+}
+
+func (s *testMyTimeSuite) TestHash(c *C) {
+	t1 := newMysqlTime(2016, 12, 31, 0, 0, 0, 0)
+	t2 := newMysqlTime(2016, 12, 31, 23, 59, 59, 999999)
+	t3 := newMysqlTime(2017, 1, 1, 0, 0, 0, 0)
+	c.Assert(t1.HashDate(), Equals, t2.HashDate())
+	c.Assert(t1.HashDate(), Not(Equals), t3.HashDate())
+	c.Assert(t1.HashDatetime(), Not(Equals), t2.HashDatetime())
+	c.Assert(t2.HashDatetime(), Not(Equals), t2.withMicrosecond(999998).HashDatetime())
+	c.Assert(t2.HashDatetime(), Equals, newMysqlTime(2016, 12, 31, 23, 59, 59, 999999).HashDatetime())
+	c.Assert(t1.HashDate(), Not(Equals), dateToUint64(t1))
+
+	// Hashes of consecutive dates should spread over the buckets evenly.
+	const buckets = 16
+	var counts [buckets]int
+	days := 0
+	for y := 2000; y < 2016; y++ {
+		for m := 1; m <= 12; m++ {
+			for d := 1; d <= lastDayOfMonth(y, m); d++ {
+				counts[newMysqlTime(y, m, d, 0, 0, 0, 0).HashDate()%buckets]++
+				days++
+			}
+		}
+	}
+	for i, n := range counts {
+		c.Assert(n > days/buckets/2 && n < days/buckets*2, IsTrue, Commentf("bucket %d has %d of %d", i, n, days))
+	}
+}
+
+func (s *testMyTimeSuite) TestTimestampAdd(c *C) {
+	base := newMysqlTime(2016, 12, 31, 23, 59, 59, 999999)
+	tbl := []struct {
+		unit   string
+		n      int64
+		expect mysqlTime
+	}{
+		{"YEAR", 1, newMysqlTime(2017, 12, 31, 23, 59, 59, 999999)},
+		{"QUARTER", -1, newMysqlTime(2016, 9, 30, 23, 59, 59, 999999)},
+		{"MONTH", 2, newMysqlTime(2017, 2, 28, 23, 59, 59, 999999)},
+		{"WEEK", 1, newMysqlTime(2017, 1, 7, 23, 59, 59, 999999)},
+		{"DAY", -366, newMysqlTime(2015, 12, 31, 23, 59, 59, 999999)},
+		{"HOUR", 25, newMysqlTime(2017, 1, 2, 0, 59, 59, 999999)},
+		{"MINUTE", -60, newMysqlTime(2016, 12, 31, 22, 59, 59, 999999)},
+		{"SECOND", 1, newMysqlTime(2017, 1, 1, 0, 0, 0, 999999)},
+		{"MICROSECOND", 1, newMysqlTime(2017, 1, 1, 0, 0, 0, 0)},
+		{"microsecond", -1000000, newMysqlTime(2016, 12, 31, 23, 59, 58, 999999)},
+		// One day in microseconds doesn't fit in 32 bits.
+		{"MICROSECOND", 86400000000, newMysqlTime(2017, 1, 1, 23, 59, 59, 999999)},
+	}
+
+	for i, t := range tbl {
+		v, err := timestampAdd(t.unit, t.n, base)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(v, Equals, t.expect, Commentf("%d failed.", i))
+		if t.unit != "QUARTER" && t.unit != "MONTH" {
+			c.Assert(timestampDiff(t.unit, base, v), Equals, t.n, Commentf("%d failed.", i))
+		}
+	}
+
+	_, err := timestampAdd("YEAR", 1, newMysqlTime(9999, 1, 1, 0, 0, 0, 0))
+	c.Assert(err, NotNil)
+	_, err = timestampAdd("DAY", 1, ZeroTime)
+	c.Assert(err, NotNil)
+	_, err = timestampAdd("UNKNOWN", 1, base)
+	c.Assert(err, NotNil)
+}
+
+// TestWeekModeMatrix checks WEEK(date, mode) and YEARWEEK(date, mode) of all the 8 modes against MySQL
+// for the first days of 2000 and 2016. 2000-01-01 is a Saturday and 2016-01-01 is a Friday.
+//
+// Mode  First day  Range  Week 1 is the first week ...
+// 0     Sunday     0-53   with a Sunday in this year
+// 1     Monday     0-53   with 4 or more days this year
+// 2     Sunday     1-53   with a Sunday in this year
+// 3     Monday     1-53   with 4 or more days this year
+// 4     Sunday     0-53   with 4 or more days this year
+// 5     Monday     0-53   with a Monday in this year
+// 6     Sunday     1-53   with 4 or more days this year
+// 7     Monday     1-53   with a Monday in this year
+func (s *testMyTimeSuite) TestWeekModeMatrix(c *C) {
+	tbl := []struct {
+		t        mysqlTime
+		week     [8]int
+		yearWeek [8]int
+	}{
+		{newMysqlTime(2000, 1, 1, 0, 0, 0, 0), [8]int{0, 0, 52, 52, 0, 0, 52, 52}, [8]int{199952, 199952, 199952, 199952, 199952, 199952, 199952, 199952}},
+		{newMysqlTime(2000, 1, 2, 0, 0, 0, 0), [8]int{1, 0, 1, 52, 1, 0, 1, 52}, [8]int{200001, 199952, 200001, 199952, 200001, 199952, 200001, 199952}},
+		{newMysqlTime(2000, 1, 3, 0, 0, 0, 0), [8]int{1, 1, 1, 1, 1, 1, 1, 1}, [8]int{200001, 200001, 200001, 200001, 200001, 200001, 200001, 200001}},
+		{newMysqlTime(2000, 1, 4, 0, 0, 0, 0), [8]int{1, 1, 1, 1, 1, 1, 1, 1}, [8]int{200001, 200001, 200001, 200001, 200001, 200001, 200001, 200001}},
+		{newMysqlTime(2000, 1, 5, 0, 0, 0, 0), [8]int{1, 1, 1, 1, 1, 1, 1, 1}, [8]int{200001, 200001, 200001, 200001, 200001, 200001, 200001, 200001}},
+		{newMysqlTime(2000, 1, 6, 0, 0, 0, 0), [8]int{1, 1, 1, 1, 1, 1, 1, 1}, [8]int{200001, 200001, 200001, 200001, 200001, 200001, 200001, 200001}},
+		{newMysqlTime(2000, 1, 7, 0, 0, 0, 0), [8]int{1, 1, 1, 1, 1, 1, 1, 1}, [8]int{200001, 200001, 200001, 200001, 200001, 200001, 200001, 200001}},
+		{newMysqlTime(2016, 1, 1, 0, 0, 0, 0), [8]int{0, 0, 52, 53, 0, 0, 52, 52}, [8]int{201552, 201553, 201552, 201553, 201552, 201552, 201552, 201552}},
+		{newMysqlTime(2016, 1, 2, 0, 0, 0, 0), [8]int{0, 0, 52, 53, 0, 0, 52, 52}, [8]int{201552, 201553, 201552, 201553, 201552, 201552, 201552, 201552}},
+		{newMysqlTime(2016, 1, 3, 0, 0, 0, 0), [8]int{1, 0, 1, 53, 1, 0, 1, 52}, [8]int{201601, 201553, 201601, 201553, 201601, 201552, 201601, 201552}},
+	}
+
+	for i, t := range tbl {
+		for mode := 0; mode < 8; mode++ {
+			c.Assert(t.t.Week(mode), Equals, t.week[mode], Commentf("%d mode %d failed.", i, mode))
+			year, week := t.t.YearWeek(mode)
+			c.Assert(year*100+week, Equals, t.yearWeek[mode], Commentf("%d mode %d failed.", i, mode))
+		}
+	}
+}
+
+func (s *testMyTimeSuite) TestYearWeekInt(c *C) {
+	tbl := []struct {
+		t      mysqlTime
+		mode   int
+		expect int
+	}{
+		{newMysqlTime(2014, 12, 29, 0, 0, 0, 0), 0, 201452},
+		{newMysqlTime(2014, 12, 29, 0, 0, 0, 0), 3, 201501},
+		{newMysqlTime(2014, 12, 28, 0, 0, 0, 0), 3, 201452},
+		{newMysqlTime(2016, 1, 1, 0, 0, 0, 0), 0, 201552},
+		{newMysqlTime(2016, 1, 1, 0, 0, 0, 0), 3, 201553},
+		{newMysqlTime(2016, 1, 3, 0, 0, 0, 0), 0, 201601},
+		{newMysqlTime(2016, 12, 31, 0, 0, 0, 0), 0, 201652},
+		{newMysqlTime(2017, 1, 1, 0, 0, 0, 0), 1, 201652},
+		{newMysqlTime(2008, 12, 31, 0, 0, 0, 0), 1, 200901},
+		{newMysqlTime(2009, 1, 1, 0, 0, 0, 0), 4, 200853},
+	}
+
+	for i, t := range tbl {
+		c.Assert(t.t.YearWeekInt(t.mode), Equals, t.expect, Commentf("%d failed.", i))
+	}
+}
+
+func (s *testMyTimeSuite) TestValidateTimeOfDay(c *C) {
+	tbl := []struct {
+		input     string
+		timeOfDay bool
+	}{
+		{"00:00:00", true},
+		{"23:59:59", true},
+		{"12:30:00.999999", true},
+		{"24:00:00", false},
+		{"25:00:00", false},
+		{"838:59:59", false},
+		{"-01:00:00", false},
+	}
+
+	for i, t := range tbl {
+		// All the inputs are valid durations.
+		d, err := ParseDuration(t.input, MaxFsp)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		err = d.toMysqlTime().ValidateTimeOfDay()
+		if t.timeOfDay {
+			c.Assert(err, IsNil, Commentf("%d failed.", i))
+		} else {
+			c.Assert(terror.ErrorEqual(err, ErrInvalidTimeFormat), IsTrue, Commentf("%d failed.", i))
+		}
+	}
+
+	c.Assert(newMysqlTime(2016, 12, 31, 23, 60, 0, 0).ValidateTimeOfDay(), NotNil)
+	c.Assert(newMysqlTime(2016, 12, 31, 23, 0, 60, 0).ValidateTimeOfDay(), NotNil)
+}
+
+func (s *testMyTimeSuite) TestGoDuration(c *C) {
+	tbl := []struct {
+		t      mysqlTime
+		expect gotime.Duration
+	}{
+		{newMysqlTime(0, 0, 0, 0, 0, 0, 0), 0},
+		{newMysqlTime(0, 0, 0, 12, 30, 15, 123456), 12*gotime.Hour + 30*gotime.Minute + 15*gotime.Second + 123456*gotime.Microsecond},
+		{newMysqlTime(0, 0, 0, 838, 59, 59, 0), MaxTime},
+		{newMysqlTime(0, 0, 0, 0, 0, 0, 1), gotime.Microsecond},
+	}
+
+	for i, t := range tbl {
+		d, err := t.t.GoDuration()
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(d, Equals, t.expect, Commentf("%d failed.", i))
+		c.Assert(Duration{Duration: d, Fsp: MaxFsp}.toMysqlTime(), Equals, t.t, Commentf("%d failed.", i))
+
+		neg := t.t
+		neg.neg = true
+		d, err = neg.GoDuration()
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(d, Equals, -t.expect, Commentf("%d failed.", i))
+		if t.expect != 0 {
+			c.Assert(Duration{Duration: d, Fsp: MaxFsp}.toMysqlTime(), Equals, neg, Commentf("%d failed.", i))
+		}
+	}
+
+	errTbl := []mysqlTime{
+		newMysqlTime(0, 0, 0, 838, 59, 59, 1),
+		newMysqlTime(0, 0, 0, 839, 0, 0, 0),
+		newMysqlTime(2016, 12, 31, 0, 0, 0, 0),
+	}
+	for i, t := range errTbl {
+		_, err := t.GoDuration()
+		c.Assert(err, NotNil, Commentf("%d failed.", i))
+	}
+}
+
+func (s *testMyTimeSuite) TestNewMysqlTimeChecked(c *C) {
+	tbl := []struct {
+		fields [7]int
+		valid  bool
+	}{
+		{[7]int{2016, 12, 31, 23, 59, 59, 999999}, true},
+		{[7]int{0, 0, 0, 0, 0, 0, 0}, true},
+		{[7]int{9999, 1, 1, 0, 0, 0, 0}, true},
+		{[7]int{10000, 1, 1, 0, 0, 0, 0}, false},
+		{[7]int{-1, 1, 1, 0, 0, 0, 0}, false},
+		{[7]int{2016, 12, 1, 0, 0, 0, 0}, true},
+		{[7]int{2016, 13, 1, 0, 0, 0, 0}, false},
+		{[7]int{2016, -1, 1, 0, 0, 0, 0}, false},
+		{[7]int{2016, 2, 29, 0, 0, 0, 0}, true},
+		{[7]int{2016, 2, 30, 0, 0, 0, 0}, false},
+		{[7]int{2016, 1, 32, 0, 0, 0, 0}, false},
+		{[7]int{2016, 1, -1, 0, 0, 0, 0}, false},
+		{[7]int{2016, 1, 1, 23, 0, 0, 0}, true},
+		{[7]int{2016, 1, 1, 24, 0, 0, 0}, false},
+		{[7]int{2016, 1, 1, -1, 0, 0, 0}, false},
+		{[7]int{2016, 1, 1, 0, 59, 0, 0}, true},
+		{[7]int{2016, 1, 1, 0, 60, 0, 0}, false},
+		{[7]int{2016, 1, 1, 0, -1, 0, 0}, false},
+		{[7]int{2016, 1, 1, 0, 0, 59, 0}, true},
+		{[7]int{2016, 1, 1, 0, 0, 60, 0}, false},
+		{[7]int{2016, 1, 1, 0, 0, -1, 0}, false},
+		{[7]int{2016, 1, 1, 0, 0, 0, 1000000}, false},
+		{[7]int{2016, 1, 1, 0, 0, 0, -1}, false},
+	}
+
+	for i, t := range tbl {
+		f := t.fields
+		v, err := NewMysqlTimeChecked(f[0], f[1], f[2], f[3], f[4], f[5], f[6])
+		if t.valid {
+			c.Assert(err, IsNil, Commentf("%d failed.", i))
+			c.Assert(v, Equals, newMysqlTime(f[0], f[1], f[2], f[3], f[4], f[5], f[6]), Commentf("%d failed.", i))
+		} else {
+			c.Assert(terror.ErrorEqual(err, ErrInvalidTimeFormat), IsTrue, Commentf("%d failed.", i))
+		}
+	}
+}
+
+func (s *testMyTimeSuite) TestFieldsOverflow(c *C) {
+	if debugCheckFields {
+		c.Skip("newMysqlTime panics on overflow in debug build")
+	}
+	// newMysqlTime wraps out-of-range day silently.
+	t := newMysqlTime(2016, 1, 256, 0, 0, 0, 0)
+	c.Assert(t.day, Equals, uint8(0))
+	c.Assert(fieldsOverflow(2016, 1, 256, 0, 0, 0, 0), IsTrue)
+	c.Assert(fieldsOverflow(2016, 1, 255, 0, 0, 0, 0), IsFalse)
+	c.Assert(fieldsOverflow(65536, 1, 1, 0, 0, 0, 0), IsTrue)
+	c.Assert(fieldsOverflow(2016, 1, 1, 0, 0, 0, -1), IsTrue)
+
+	// The checked paths reject it.
+	_, err := NewMysqlTimeChecked(2016, 1, 256, 0, 0, 0, 0)
+	c.Assert(terror.ErrorEqual(err, ErrInvalidTimeFormat), IsTrue)
+	for i, str := range []string{"2016-01-256", "2016-257-01", "67536-01-01"} {
+		_, err = ParseDatetime(str)
+		c.Assert(err, NotNil, Commentf("%d failed.", i))
+	}
+}
+
+func (s *testMyTimeSuite) TestExportedChecked(c *C) {
+	_, err := FromDate(2016, 1, 256, 0, 0, 0, 0)
+	c.Assert(terror.ErrorEqual(err, ErrInvalidTimeFormat), IsTrue)
+	_, err = FromDate(2016, 2, 30, 0, 0, 0, 0)
+	c.Assert(terror.ErrorEqual(err, ErrInvalidTimeFormat), IsTrue)
+	t, err := FromDate(2016, 0, 0, 12, 30, 0, 0)
+	c.Assert(err, IsNil)
+	c.Assert(t, Equals, TimeInternal(newMysqlTime(2016, 0, 0, 12, 30, 0, 0)))
+
+	// The exported functions rebuilding values from the fields reject invalid ones too,
+	// rather than computing on them.
+	invalid := mysqlTime{year: 2016, month: 2, day: 30}
+	_, err = AddMonths(invalid, 1)
+	c.Assert(terror.ErrorEqual(err, ErrInvalidTimeFormat), IsTrue)
+	_, err = AddInterval(invalid, Interval{Day: 1})
+	c.Assert(terror.ErrorEqual(err, ErrInvalidTimeFormat), IsTrue)
+	_, err = DateRange(invalid, newMysqlTime(2016, 3, 31, 0, 0, 0, 0), "DAY", 1)
+	c.Assert(terror.ErrorEqual(err, ErrInvalidTimeFormat), IsTrue)
+	_, err = ConvertTZ(invalid, gotime.UTC, gotime.UTC)
+	c.Assert(terror.ErrorEqual(err, ErrInvalidTimeFormat), IsTrue)
+}
+
+func (s *testMyTimeSuite) TestDatePartTimePart(c *C) {
+	tbl := []struct {
+		t        mysqlTime
+		datePart mysqlTime
+		timePart mysqlTime
+	}{
+		{newMysqlTime(2016, 12, 31, 23, 59, 59, 999999), newMysqlTime(2016, 12, 31, 0, 0, 0, 0), newMysqlTime(0, 0, 0, 23, 59, 59, 999999)},
+		{newMysqlTime(2016, 12, 31, 0, 0, 0, 0), newMysqlTime(2016, 12, 31, 0, 0, 0, 0), ZeroTime},
+		{newMysqlTime(0, 0, 0, 12, 0, 0, 1), ZeroTime, newMysqlTime(0, 0, 0, 12, 0, 0, 1)},
+		{ZeroTime, ZeroTime, ZeroTime},
+	}
+
+	for i, t := range tbl {
+		d, tm := t.t.DatePart(), t.t.TimePart()
+		c.Assert(d, Equals, t.datePart, Commentf("%d failed.", i))
+		c.Assert(tm, Equals, t.timePart, Commentf("%d failed.", i))
+		// Recombine the two parts.
+		c.Assert(d.withHour(tm.Hour()).withMinute(tm.Minute()).withSecond(tm.Second()).withMicrosecond(tm.Microsecond()), Equals, t.t, Commentf("%d failed.", i))
+	}
+}
+
+func (s *testMyTimeSuite) TestNow(c *C) {
+	defer func(f func() gotime.Time) { nowFunc = f }(nowFunc)
+
+	loc := gotime.FixedZone("UTC+8", 8*3600)
+	tbl := []struct {
+		clock   gotime.Time
+		fsp     int
+		now     mysqlTime
+		curDate mysqlTime
+		curTime mysqlTime
+	}{
+		{
+			gotime.Date(2016, 12, 31, 1, 2, 3, 123456789, gotime.UTC), 0,
+			newMysqlTime(2016, 12, 31, 9, 2, 3, 0),
+			newMysqlTime(2016, 12, 31, 0, 0, 0, 0),
+			newMysqlTime(0, 0, 0, 9, 2, 3, 0),
+		},
+		{
+			gotime.Date(2016, 12, 31, 1, 2, 3, 123456789, gotime.UTC), 3,
+			newMysqlTime(2016, 12, 31, 9, 2, 3, 123000).withFsp(3),
+			newMysqlTime(2016, 12, 31, 0, 0, 0, 0),
+			newMysqlTime(0, 0, 0, 9, 2, 3, 123000).withFsp(3),
+		},
+		{
+			gotime.Date(2016, 12, 31, 1, 2, 3, 123456789, gotime.UTC), 6,
+			newMysqlTime(2016, 12, 31, 9, 2, 3, 123456).withFsp(6),
+			newMysqlTime(2016, 12, 31, 0, 0, 0, 0),
+			newMysqlTime(0, 0, 0, 9, 2, 3, 123456).withFsp(6),
+		},
+		// The date in loc is already the next day.
+		{
+			gotime.Date(2016, 12, 31, 16, 0, 0, 0, gotime.UTC), 0,
+			newMysqlTime(2017, 1, 1, 0, 0, 0, 0),
+			newMysqlTime(2017, 1, 1, 0, 0, 0, 0),
+			newMysqlTime(0, 0, 0, 0, 0, 0, 0),
+		},
+		// Rounding carries to the next day, but CurDate doesn't.
+		{
+			gotime.Date(2016, 12, 31, 15, 59, 59, 500000000, gotime.UTC), 0,
+			newMysqlTime(2017, 1, 1, 0, 0, 0, 0),
+			newMysqlTime(2016, 12, 31, 0, 0, 0, 0),
+			newMysqlTime(0, 0, 0, 0, 0, 0, 0),
+		},
+		// Invalid fsp is treated as DefaultFsp.
+		{
+			gotime.Date(2016, 12, 31, 1, 2, 3, 123456789, gotime.UTC), 7,
+			newMysqlTime(2016, 12, 31, 9, 2, 3, 0),
+			newMysqlTime(2016, 12, 31, 0, 0, 0, 0),
+			newMysqlTime(0, 0, 0, 9, 2, 3, 0),
+		},
+	}
+
+	for i, t := range tbl {
+		clock := t.clock
+		nowFunc = func() gotime.Time { return clock }
+		c.Assert(Now(loc, t.fsp), Equals, t.now, Commentf("%d failed.", i))
+		c.Assert(CurDate(loc), Equals, t.curDate, Commentf("%d failed.", i))
+		c.Assert(CurTime(loc, t.fsp), Equals, t.curTime, Commentf("%d failed.", i))
+	}
+}
+
+func (s *testMyTimeSuite) TestTimestampDiffDST(c *C) {
+	// Clocks in the US sprang forward at 2016-03-13 02:00:00, so the wall clock day before it
+	// only had 23 hours. timestampDiff works on the fields without any time zone like MySQL,
+	// so the DST transition doesn't affect the result.
+	tbl := []struct {
+		unit   string
+		t1     mysqlTime
+		t2     mysqlTime
+		expect int64
+	}{
+		{"DAY", newMysqlTime(2016, 3, 12, 12, 0, 0, 0), newMysqlTime(2016, 3, 13, 12, 0, 0, 0), 1},
+		{"HOUR", newMysqlTime(2016, 3, 12, 12, 0, 0, 0), newMysqlTime(2016, 3, 13, 12, 0, 0, 0), 24},
+		{"HOUR", newMysqlTime(2016, 3, 13, 1, 30, 0, 0), newMysqlTime(2016, 3, 13, 3, 30, 0, 0), 2},
+		{"DAY", newMysqlTime(2016, 3, 13, 3, 0, 0, 0), newMysqlTime(2016, 3, 12, 3, 0, 0, 0), -1},
+		{"WEEK", newMysqlTime(2016, 3, 6, 12, 0, 0, 0), newMysqlTime(2016, 3, 13, 12, 0, 0, 0), 1},
+		{"WEEK", newMysqlTime(2016, 3, 6, 12, 0, 0, 0), newMysqlTime(2016, 3, 13, 11, 59, 59, 999999), 0},
+		// Fall back at 2016-11-06 02:00:00.
+		{"DAY", newMysqlTime(2016, 11, 5, 12, 0, 0, 0), newMysqlTime(2016, 11, 6, 12, 0, 0, 0), 1},
+		{"WEEK", newMysqlTime(2016, 11, 6, 0, 0, 0, 0), newMysqlTime(2016, 10, 30, 0, 0, 0, 0), -1},
+	}
+
+	for i, t := range tbl {
+		c.Assert(timestampDiff(t.unit, t.t1, t.t2), Equals, t.expect, Commentf("%d failed.", i))
+	}
+
+	// Go time in the zone sees only 23 hours across the transition.
+	loc, err := gotime.LoadLocation("America/New_York")
+	if err != nil {
+		c.Skip("time zone database isn't available")
+	}
+	g1 := gotime.Date(2016, 3, 12, 12, 0, 0, 0, loc)
+	g2 := gotime.Date(2016, 3, 13, 12, 0, 0, 0, loc)
+	c.Assert(g2.Sub(g1), Equals, 23*gotime.Hour)
+}
+
+func (s *testMyTimeSuite) TestTimestampDiffFloat(c *C) {
+	t1 := newMysqlTime(2016, 12, 30, 12, 0, 0, 0)
+	t2 := newMysqlTime(2017, 1, 1, 0, 0, 0, 0)
+	tbl := []struct {
+		unit      string
+		t1        mysqlTime
+		t2        mysqlTime
+		expect    float64
+		expectInt int64
+	}{
+		{"DAY", t1, t2, 1.5, 1},
+		{"DAY", t2, t1, -1.5, -1},
+		{"WEEK", t1, t2, 1.5 / 7, 0},
+		{"HOUR", t1, t2, 36, 36},
+		{"MINUTE", t1, t2, 2160, 2160},
+		{"SECOND", t1, newMysqlTime(2016, 12, 30, 12, 0, 1, 500000), 1.5, 1},
+		{"MICROSECOND", t1, newMysqlTime(2016, 12, 30, 12, 0, 0, 1), 1, 1},
+		{"MONTH", t1, t2, 0, 0},
+		{"YEAR", newMysqlTime(2015, 1, 1, 0, 0, 0, 0), t2, 2, 2},
+		{"UNKNOWN", t1, t2, 0, 0},
+	}
+
+	for i, t := range tbl {
+		c.Assert(timestampDiffFloat(t.unit, t.t1, t.t2), Equals, t.expect, Commentf("%d failed.", i))
+		c.Assert(timestampDiff(t.unit, t.t1, t.t2), Equals, t.expectInt, Commentf("%d failed.", i))
+	}
+}
+
+func (s *testMyTimeSuite) TestDatetimeToUint64(c *C) {
+	c.Assert(datetimeToUint64(newMysqlTime(9999, 12, 31, 23, 59, 59, 999999)), Equals, uint64(99991231235959))
+	c.Assert(datetimeToUint64(ZeroTime), Equals, uint64(0))
+	// Every field at the maximum of its storage type.
+	widest := mysqlTime{year: math.MaxUint16, month: math.MaxUint8, day: math.MaxUint8, hour: math.MaxUint32, minute: math.MaxUint8, second: math.MaxUint8}
+	c.Assert(datetimeToUint64(widest) < 8e14, IsTrue)
+
+	tbl := []struct {
+		t          mysqlTime
+		fsp        int
+		expectInt  uint64
+		expectFrac uint64
+	}{
+		{newMysqlTime(2016, 12, 31, 23, 59, 59, 123456), 0, 20161231235959, 0},
+		{newMysqlTime(2016, 12, 31, 23, 59, 59, 123456), 3, 20161231235959, 123},
+		{newMysqlTime(2016, 12, 31, 23, 59, 59, 123456), 6, 20161231235959, 123456},
+		{newMysqlTime(2016, 12, 31, 23, 59, 59, 1), 6, 20161231235959, 1},
+		{newMysqlTime(1000, 1, 1, 0, 0, 0, 1), 6, 10000101000000, 1},
+		{newMysqlTime(9999, 12, 31, 23, 59, 59, 999999), 5, 99991231235959, 99999},
+		{newMysqlTime(9999, 12, 31, 23, 59, 59, 999999), 6, 99991231235959, 999999},
+	}
+	for i, t := range tbl {
+		v, frac, err := datetimeToUint64WithFsp(t.t, t.fsp)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(v, Equals, t.expectInt, Commentf("%d failed.", i))
+		c.Assert(frac, Equals, t.expectFrac, Commentf("%d failed.", i))
+	}
+
+	_, _, err := datetimeToUint64WithFsp(ZeroTime, 7)
+	c.Assert(err, NotNil)
+}
+
+func (s *testMyTimeSuite) TestGoTimeClamped(c *C) {
+	tbl := []struct {
+		t      mysqlTime
+		expect gotime.Time
+		valid  bool
+	}{
+		{newMysqlTime(2016, 12, 31, 23, 59, 59, 123456), gotime.Date(2016, 12, 31, 23, 59, 59, 123456000, gotime.UTC), true},
+		{newMysqlTime(2006, 12, 0, 0, 0, 0, 0), gotime.Date(2006, 11, 30, 0, 0, 0, 0, gotime.UTC), false},
+		{newMysqlTime(2006, 0, 15, 0, 0, 0, 0), gotime.Date(2005, 12, 15, 0, 0, 0, 0, gotime.UTC), false},
+		{newMysqlTime(2016, 2, 30, 0, 0, 0, 0), gotime.Date(2016, 3, 1, 0, 0, 0, 0, gotime.UTC), false},
+		{ZeroTime, gotime.Date(-1, 11, 30, 0, 0, 0, 0, gotime.UTC), false},
+		{newMysqlTime(0, 0, 0, 25, 0, 0, 0), gotime.Date(-1, 12, 1, 1, 0, 0, 0, gotime.UTC), false},
+	}
+
+	for i, t := range tbl {
+		c.Assert(t.t.GoTimeClamped(gotime.UTC), Equals, t.expect, Commentf("%d failed.", i))
+		tm, err := t.t.GoTime(gotime.UTC)
+		if t.valid {
+			c.Assert(err, IsNil, Commentf("%d failed.", i))
+			c.Assert(tm, Equals, t.expect, Commentf("%d failed.", i))
+		} else {
+			c.Assert(err, NotNil, Commentf("%d failed.", i))
+		}
+	}
+}
+
+func (s *testMyTimeSuite) TestGoTimeBatch(c *C) {
+	ts := []mysqlTime{
+		newMysqlTime(2016, 12, 31, 23, 59, 59, 123456),
+		newMysqlTime(2017, 1, 1, 0, 0, 0, 0),
+	}
+	out := make([]gotime.Time, len(ts))
+	idx, err := GoTimeBatch(ts, gotime.UTC, out)
+	c.Assert(err, IsNil)
+	c.Assert(idx, Equals, -1)
+	for i, t := range ts {
+		tm, err := t.GoTime(gotime.UTC)
+		c.Assert(err, IsNil)
+		c.Assert(out[i], Equals, tm, Commentf("%d failed.", i))
+	}
+
+	ts = append(ts, newMysqlTime(2006, 12, 0, 0, 0, 0, 0), newMysqlTime(0, 0, 0, 25, 0, 0, 0), newMysqlTime(2016, 1, 1, 0, 0, 0, 0))
+	out = make([]gotime.Time, len(ts))
+	idx, err = GoTimeBatch(ts, gotime.UTC, out)
+	c.Assert(terror.ErrorEqual(err, ErrZeroDateNotAllowed), IsTrue)
+	c.Assert(idx, Equals, 2)
+	// The elements after the invalid one are still converted.
+	for i, t := range ts {
+		c.Assert(out[i], Equals, t.GoTimeClamped(gotime.UTC), Commentf("%d failed.", i))
+	}
+
+	_, err = GoTimeBatch(ts, gotime.UTC, out[:1])
+	c.Assert(err, NotNil)
+
+	// The error of the first invalid element is the one GoTime returns for it.
+	newYork, err := gotime.LoadLocation("America/New_York")
+	c.Assert(err, IsNil)
+	valid := newMysqlTime(2016, 1, 1, 0, 0, 0, 0)
+	for i, t := range []mysqlTime{
+		newMysqlTime(2016, 0, 1, 0, 0, 0, 0),
+		newMysqlTime(2016, 2, 30, 0, 0, 0, 0),
+		newMysqlTime(2016, 1, 1, 24, 0, 0, 0),
+		SecToTime(-3600, 0),
+		newMysqlTime(2016, 3, 13, 2, 30, 0, 0),
+	} {
+		_, expect := t.GoTime(newYork)
+		c.Assert(expect, NotNil, Commentf("%d failed.", i))
+		idx, err = GoTimeBatch([]mysqlTime{valid, t, ZeroTime}, newYork, out)
+		c.Assert(idx, Equals, 1, Commentf("%d failed.", i))
+		c.Assert(terror.ErrorEqual(err, expect), IsTrue, Commentf("%d failed.", i))
+	}
+}
+
+func (s *testMyTimeSuite) TestCompareWithFsp(c *C) {
+	tbl := []struct {
+		t1     mysqlTime
+		t2     mysqlTime
+		fsp    int
+		expect int
+	}{
+		// Rounding makes them equal.
+		{newMysqlTime(2016, 12, 31, 23, 59, 59, 600000), newMysqlTime(2017, 1, 1, 0, 0, 0, 0), 0, 0},
+		{newMysqlTime(2016, 12, 31, 23, 59, 59, 600000), newMysqlTime(2017, 1, 1, 0, 0, 0, 0), 6, -1},
+		{newMysqlTime(2016, 1, 1, 0, 0, 0, 123456), newMysqlTime(2016, 1, 1, 0, 0, 0, 123000), 3, 0},
+		{newMysqlTime(2016, 1, 1, 0, 0, 0, 123456), newMysqlTime(2016, 1, 1, 0, 0, 0, 123000), 4, 1},
+		// Rounding makes them different.
+		{newMysqlTime(2016, 1, 1, 0, 0, 0, 499999), newMysqlTime(2016, 1, 1, 0, 0, 0, 500000), 0, -1},
+		{newMysqlTime(2016, 1, 1, 0, 0, 0, 499999), newMysqlTime(2016, 1, 1, 0, 0, 0, 500000), 6, -1},
+		{newMysqlTime(2016, 1, 1, 0, 0, 0, 500000), newMysqlTime(2016, 1, 1, 0, 0, 0, 499999), UnspecifiedFsp, 1},
+		{SecToTime(-10, -400000), SecToTime(-10, 0), 0, 0},
+		{SecToTime(-10, -500000), SecToTime(-10, 0), 0, -1},
+		{newMysqlTime(9999, 12, 31, 23, 59, 59, 500000), newMysqlTime(9999, 12, 31, 23, 59, 59, 0), 0, 0},
+	}
+
+	for i, t := range tbl {
+		c.Assert(CompareWithFsp(t.t1, t.t2, t.fsp), Equals, t.expect, Commentf("%d failed.", i))
+		c.Assert(CompareWithFsp(t.t2, t.t1, t.fsp), Equals, -t.expect, Commentf("%d failed.", i))
+	}
+	c.Assert(Compare(newMysqlTime(2016, 12, 31, 23, 59, 59, 600000), newMysqlTime(2017, 1, 1, 0, 0, 0, 0)), Equals, -1)
+}
+
+func (s *testMyTimeSuite) TestWeekdayNameMonthName(c *C) {
+	tbl := []struct {
+		t       mysqlTime
+		weekday string
+		month   string
+	}{
+		{newMysqlTime(2016, 12, 31, 0, 0, 0, 0), "Saturday", "December"},
+		{newMysqlTime(2017, 1, 1, 0, 0, 0, 0), "Sunday", "January"},
+		{newMysqlTime(2016, 2, 29, 0, 0, 0, 0), "Monday", "February"},
+		{newMysqlTime(2000, 1, 1, 0, 0, 0, 0), "Saturday", "January"},
+		{newMysqlTime(2016, 6, 0, 0, 0, 0, 0), "", "June"},
+		{ZeroTime, "", ""},
+	}
+
+	for i, t := range tbl {
+		c.Assert(t.t.WeekdayName(), Equals, t.weekday, Commentf("%d failed.", i))
+		c.Assert(t.t.MonthName(), Equals, t.month, Commentf("%d failed.", i))
+		// Consistent with DATE_FORMAT.
+		str, err := t.t.Format("%W")
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(str, Equals, t.weekday, Commentf("%d failed.", i))
+		if t.t.month != 0 {
+			str, err = t.t.Format("%M")
+			c.Assert(err, IsNil, Commentf("%d failed.", i))
+			c.Assert(str, Equals, t.month, Commentf("%d failed.", i))
+		}
+	}
+
+	// The names follow the given TimeNames like DATE_FORMAT.
+	frFR := &TimeNames{
+		MonthNames: []string{
+			"janvier", "février", "mars", "avril", "mai", "juin",
+			"juillet", "août", "septembre", "octobre", "novembre", "décembre",
+		},
+		AbbrevMonthNames: []string{
+			"janv.", "févr.", "mars", "avr.", "mai", "juin",
+			"juil.", "août", "sept.", "oct.", "nov.", "déc.",
+		},
+		WeekdayNames:       []string{"lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi", "dimanche"},
+		AbbrevWeekdayNames: []string{"lun.", "mar.", "mer.", "jeu.", "ven.", "sam.", "dim."},
+	}
+	t := newMysqlTime(2016, 12, 31, 0, 0, 0, 0)
+	weekday, err := t.WeekdayNameWithNames(frFR)
+	c.Assert(err, IsNil)
+	c.Assert(weekday, Equals, "samedi")
+	month, err := t.MonthNameWithNames(frFR)
+	c.Assert(err, IsNil)
+	c.Assert(month, Equals, "décembre")
+	str, err := formatTimeWithNames(t, "%W %M", frFR)
+	c.Assert(err, IsNil)
+	c.Assert(str, Equals, weekday+" "+month)
+
+	// Invalid names are rejected rather than panic.
+	for i, names := range []*TimeNames{nil, {}, {WeekdayNames: []string{"lundi"}, MonthNames: []string{"janvier"}}} {
+		_, err = t.WeekdayNameWithNames(names)
+		c.Assert(err, NotNil, Commentf("%d failed.", i))
+		_, err = t.MonthNameWithNames(names)
+		c.Assert(err, NotNil, Commentf("%d failed.", i))
+	}
+}
+
+func (s *testMyTimeSuite) TestSubInterval(c *C) {
+	tbl := []struct {
+		t      mysqlTime
+		unit   string
+		amount int
+		expect mysqlTime
+	}{
+		{newMysqlTime(2016, 3, 31, 0, 0, 0, 0), "MONTH", 1, newMysqlTime(2016, 2, 29, 0, 0, 0, 0)},
+		{newMysqlTime(2015, 3, 31, 0, 0, 0, 0), "MONTH", 1, newMysqlTime(2015, 2, 28, 0, 0, 0, 0)},
+		{newMysqlTime(2016, 5, 31, 12, 0, 0, 0), "MONTH", 1, newMysqlTime(2016, 4, 30, 12, 0, 0, 0)},
+		{newMysqlTime(2016, 3, 31, 0, 0, 0, 0), "MONTH", 13, newMysqlTime(2015, 2, 28, 0, 0, 0, 0)},
+		{newMysqlTime(2016, 3, 31, 0, 0, 0, 0), "MONTH", -1, newMysqlTime(2016, 4, 30, 0, 0, 0, 0)},
+		{newMysqlTime(2016, 2, 29, 0, 0, 0, 0), "YEAR", 1, newMysqlTime(2015, 2, 28, 0, 0, 0, 0)},
+		{newMysqlTime(2016, 2, 29, 0, 0, 0, 0), "YEAR", 4, newMysqlTime(2012, 2, 29, 0, 0, 0, 0)},
+		{newMysqlTime(2016, 5, 31, 0, 0, 0, 0), "QUARTER", 1, newMysqlTime(2016, 2, 29, 0, 0, 0, 0)},
+		{newMysqlTime(2016, 1, 31, 0, 0, 0, 0), "MONTH", 1, newMysqlTime(2015, 12, 31, 0, 0, 0, 0)},
+		{newMysqlTime(2016, 3, 1, 0, 0, 0, 0), "DAY", 1, newMysqlTime(2016, 2, 29, 0, 0, 0, 0)},
+		{newMysqlTime(2017, 1, 1, 0, 0, 0, 0), "MICROSECOND", 1, newMysqlTime(2016, 12, 31, 23, 59, 59, 999999)},
+		{newMysqlTime(2017, 1, 1, 0, 0, 0, 0), "HOUR", -1, newMysqlTime(2017, 1, 1, 1, 0, 0, 0)},
+	}
+
+	for i, t := range tbl {
+		v, err := t.t.SubInterval(t.unit, t.amount)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(v, Equals, t.expect, Commentf("%d failed.", i))
+		v, err = t.t.AddInterval(t.unit, -t.amount)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(v, Equals, t.expect, Commentf("%d failed.", i))
+
+		m, err := AddMonths(t.t, -1)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		sub, err := t.t.SubInterval("MONTH", 1)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(m, Equals, sub, Commentf("%d failed.", i))
+	}
+
+	_, err := newMysqlTime(1, 1, 1, 0, 0, 0, 0).SubInterval("YEAR", 2)
+	c.Assert(err, NotNil)
+	for i, unit := range []string{"DAY", "MONTH", "SECOND", "MICROSECOND"} {
+		_, err = newMysqlTime(2016, 1, 1, 0, 0, 0, 0).SubInterval(unit, -int(^uint(0)>>1)-1)
+		c.Assert(terror.ErrorEqual(err, ErrDatetimeOutOfRange), IsTrue, Commentf("%d failed.", i))
+	}
+	_, err = AddMonths(ZeroTime, 1)
+	c.Assert(err, NotNil)
+}
+
+func (s *testMyTimeSuite) TestAddIntervalFields(c *C) {
+	tbl := []struct {
+		t      mysqlTime
+		iv     Interval
+		expect mysqlTime
+	}{
+		{newMysqlTime(2016, 1, 31, 0, 0, 0, 0), Interval{Year: 1, Month: 1}, newMysqlTime(2017, 2, 28, 0, 0, 0, 0)},
+		{newMysqlTime(2016, 3, 31, 0, 0, 0, 0), Interval{Year: -1, Month: -1}, newMysqlTime(2015, 2, 28, 0, 0, 0, 0)},
+		// The month is added before the day, the clamped day is not restored.
+		{newMysqlTime(2016, 1, 30, 0, 0, 0, 0), Interval{Month: 1, Day: 1}, newMysqlTime(2016, 3, 1, 0, 0, 0, 0)},
+		{newMysqlTime(2016, 1, 31, 0, 0, 0, 0), Interval{Month: 1, Day: -1}, newMysqlTime(2016, 2, 28, 0, 0, 0, 0)},
+		{newMysqlTime(2016, 1, 31, 23, 0, 0, 0), Interval{Month: 1, Hour: 1}, newMysqlTime(2016, 3, 1, 0, 0, 0, 0)},
+		{newMysqlTime(2016, 3, 31, 0, 0, 0, 0), Interval{Month: -1, Microsecond: -1}, newMysqlTime(2016, 2, 28, 23, 59, 59, 999999)},
+		{newMysqlTime(2016, 12, 31, 23, 59, 59, 0), Interval{Day: 1, Hour: 2, Minute: 3, Second: 4}, newMysqlTime(2017, 1, 2, 2, 3, 3, 0)},
+		{newMysqlTime(2016, 1, 1, 0, 0, 0, 0), Interval{}, newMysqlTime(2016, 1, 1, 0, 0, 0, 0)},
+	}
+
+	for i, t := range tbl {
+		v, err := t.t.AddIntervalFields(t.iv)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(v, Equals, t.expect, Commentf("%d failed.", i))
+	}
+
+	_, err := newMysqlTime(9999, 12, 1, 0, 0, 0, 0).AddIntervalFields(Interval{Month: 1})
+	c.Assert(err, NotNil)
+	_, err = newMysqlTime(9999, 12, 31, 0, 0, 0, 0).AddIntervalFields(Interval{Day: 1})
+	c.Assert(err, NotNil)
+	_, err = ZeroTime.AddIntervalFields(Interval{Day: 1})
+	c.Assert(err, NotNil)
+}
+
+func (s *testMyTimeSuite) TestYearsBetween(c *C) {
+	tbl := []struct {
+		t1     mysqlTime
+		t2     mysqlTime
+		expect int64
+	}{
+		{newMysqlTime(1990, 6, 15, 0, 0, 0, 0), newMysqlTime(2016, 6, 14, 0, 0, 0, 0), 25},
+		{newMysqlTime(1990, 6, 15, 0, 0, 0, 0), newMysqlTime(2016, 6, 15, 0, 0, 0, 0), 26},
+		{newMysqlTime(1990, 6, 15, 12, 0, 0, 0), newMysqlTime(2016, 6, 15, 11, 59, 59, 0), 25},
+		// Leap day birthdays are reached on March 1 in common years.
+		{newMysqlTime(2016, 2, 29, 0, 0, 0, 0), newMysqlTime(2017, 2, 28, 0, 0, 0, 0), 0},
+		{newMysqlTime(2016, 2, 29, 0, 0, 0, 0), newMysqlTime(2017, 3, 1, 0, 0, 0, 0), 1},
+		{newMysqlTime(2016, 2, 29, 0, 0, 0, 0), newMysqlTime(2020, 2, 28, 0, 0, 0, 0), 3},
+		{newMysqlTime(2016, 2, 29, 0, 0, 0, 0), newMysqlTime(2020, 2, 29, 0, 0, 0, 0), 4},
+		{newMysqlTime(2015, 2, 28, 0, 0, 0, 0), newMysqlTime(2016, 2, 29, 0, 0, 0, 0), 1},
+		{newMysqlTime(2016, 6, 15, 0, 0, 0, 0), newMysqlTime(2016, 6, 15, 0, 0, 0, 0), 0},
+		{newMysqlTime(2016, 6, 15, 0, 0, 0, 0), newMysqlTime(1990, 6, 16, 0, 0, 0, 0), -25},
+	}
+
+	for i, t := range tbl {
+		c.Assert(YearsBetween(t.t1, t.t2), Equals, t.expect, Commentf("%d failed.", i))
+		c.Assert(YearsBetween(t.t1, t.t2), Equals, timestampDiff("YEAR", t.t1, t.t2), Commentf("%d failed.", i))
+	}
+}
+
+func (s *testMyTimeSuite) TestGob(c *C) {
+	values := []mysqlTime{
+		ZeroTime,
+		SecToTime(-3600, -1),
+		newMysqlTime(0, 0, 0, 838, 59, 59, 0),
+		newMysqlTime(1, 1, 1, 0, 0, 0, 0),
+		newMysqlTime(2016, 2, 29, 12, 30, 45, 123456),
+		newMysqlTime(9999, 12, 31, 23, 59, 59, 999999),
+		newMysqlTime(0, 0, 0, 10, 0, 0, 0).withFsp(2),
+		newMysqlTime(2016, 1, 1, 10, 0, 0, 0).withFsp(6),
+	}
+
+	var buf bytes.Buffer
+	enc := gob.NewEncoder(&buf)
+	for i, t := range values {
+		c.Assert(enc.Encode(t), IsNil, Commentf("%d failed.", i))
+	}
+	dec := gob.NewDecoder(&buf)
+	for i, t := range values {
+		var result mysqlTime
+		c.Assert(dec.Decode(&result), IsNil, Commentf("%d failed.", i))
+		c.Assert(result, Equals, t, Commentf("%d failed.", i))
+		c.Assert(result.String(), Equals, t.String(), Commentf("%d failed.", i))
+	}
+
+	// The fields of a struct are encoded through GobEncode too.
+	type row struct {
+		ID int
+		T  mysqlTime
+	}
+	buf.Reset()
+	in := row{ID: 1, T: newMysqlTime(2016, 12, 31, 23, 59, 59, 999999)}
+	c.Assert(gob.NewEncoder(&buf).Encode(in), IsNil)
+	var out row
+	c.Assert(gob.NewDecoder(&buf).Decode(&out), IsNil)
+	c.Assert(out, Equals, in)
+
+	var result mysqlTime
+	c.Assert(result.GobDecode([]byte{1, 2, 3}), NotNil)
+	_, err := mysqlTime{hour: 0x10000}.GobEncode()
+	c.Assert(err, NotNil)
+}
+
+func (s *testMyTimeSuite) TestScanValue(c *C) {
+	var _ sql.Scanner = &mysqlTime{}
+	var _ driver.Valuer = mysqlTime{}
+
+	tbl := []struct {
+		src    interface{}
+		expect mysqlTime
+	}{
+		{"2016-12-31 23:59:59", newMysqlTime(2016, 12, 31, 23, 59, 59, 0)},
+		{[]byte("2016-12-31 23:59:59"), newMysqlTime(2016, 12, 31, 23, 59, 59, 0)},
+		{"2016-12-31 23:59:59.123", newMysqlTime(2016, 12, 31, 23, 59, 59, 123000).withFsp(3)},
+		{[]byte("2016-02-29"), newMysqlTime(2016, 2, 29, 0, 0, 0, 0)},
+		{"0000-00-00", ZeroTime},
+		{[]byte("0000-00-00 00:00:00"), ZeroTime},
+		{gotime.Date(2016, 12, 31, 23, 59, 59, 999999000, gotime.UTC), newMysqlTime(2016, 12, 31, 23, 59, 59, 999999)},
+		{gotime.Date(2016, 2, 29, 0, 0, 0, 1000, gotime.Local), newMysqlTime(2016, 2, 29, 0, 0, 0, 1)},
+	}
+
+	for i, t := range tbl {
+		var result mysqlTime
+		c.Assert(result.Scan(t.src), IsNil, Commentf("%d failed.", i))
+		c.Assert(result, Equals, t.expect, Commentf("%d failed.", i))
+
+		v, err := result.Value()
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		var back mysqlTime
+		c.Assert(back.Scan(v), IsNil, Commentf("%d failed.", i))
+		c.Assert(Compare(back, result), Equals, 0, Commentf("%d failed.", i))
+	}
+
+	errTbl := []interface{}{
+		123,
+		"2016-13-01",
+		[]byte("abc"),
+	}
+	for i, src := range errTbl {
+		var result mysqlTime
+		c.Assert(result.Scan(src), NotNil, Commentf("%d failed.", i))
+	}
+
+	// SQL NULL is rejected rather than scanned as the zero datetime, the receiver is unchanged.
+	result := newMysqlTime(2016, 12, 31, 23, 59, 59, 0).withFsp(3)
+	c.Assert(result.Scan(nil), NotNil)
+	c.Assert(result, Equals, newMysqlTime(2016, 12, 31, 23, 59, 59, 0).withFsp(3))
+
+	// Negative TIME value can't be scanned back, so Value rejects it.
+	neg := SecToTime(-45000, 0)
+	_, err := neg.Value()
+	c.Assert(terror.ErrorEqual(err, ErrInvalidTimeFormat), IsTrue)
+	c.Assert(result.Scan(neg.String()), NotNil)
+	v, err := SecToTime(45000, 0).Value()
+	c.Assert(err, IsNil)
+	c.Assert(result.Scan(v), IsNil)
+	c.Assert(result, Equals, newMysqlTime(0, 0, 0, 12, 30, 0, 0))
+}
+
+func (s *testMyTimeSuite) TestDaysBetween(c *C) {
+	tbl := []struct {
+		t1      mysqlTime
+		t2      mysqlTime
+		expect  int
+		dayDiff int64
+	}{
+		{newMysqlTime(2016, 1, 1, 0, 0, 0, 0), newMysqlTime(2016, 1, 2, 0, 0, 0, 0), 1, 1},
+		{newMysqlTime(2016, 1, 1, 23, 0, 0, 0), newMysqlTime(2016, 1, 2, 1, 0, 0, 0), 1, 0},
+		{newMysqlTime(2016, 1, 1, 12, 0, 0, 0), newMysqlTime(2016, 1, 3, 11, 59, 59, 999999), 2, 1},
+		{newMysqlTime(2016, 1, 2, 1, 0, 0, 0), newMysqlTime(2016, 1, 1, 23, 0, 0, 0), -1, 0},
+		{newMysqlTime(2016, 1, 1, 0, 0, 0, 0), newMysqlTime(2016, 1, 1, 23, 59, 59, 0), 0, 0},
+		{newMysqlTime(2016, 2, 28, 0, 0, 0, 0), newMysqlTime(2016, 3, 1, 0, 0, 0, 0), 2, 2},
+		{newMysqlTime(2015, 12, 31, 23, 59, 59, 0), newMysqlTime(2017, 1, 1, 0, 0, 0, 0), 367, 366},
+	}
+
+	for i, t := range tbl {
+		c.Assert(DaysBetween(t.t1, t.t2), Equals, t.expect, Commentf("%d failed.", i))
+		c.Assert(timestampDiff("DAY", t.t1, t.t2), Equals, t.dayDiff, Commentf("%d failed.", i))
+	}
+}
+
+func (s *testMyTimeSuite) TestDateDiff(c *C) {
+	tbl := []struct {
+		t1     mysqlTime
+		t2     mysqlTime
+		expect int64
+	}{
+		{newMysqlTime(2016, 1, 1, 23, 59, 0, 0), newMysqlTime(2015, 12, 31, 0, 0, 0, 0), 1},
+		{newMysqlTime(2015, 12, 31, 0, 0, 0, 0), newMysqlTime(2016, 1, 1, 23, 59, 0, 0), -1},
+		{newMysqlTime(2008, 12, 31, 23, 59, 59, 1), newMysqlTime(2008, 12, 30, 1, 1, 1, 2), 1},
+		{newMysqlTime(2016, 1, 1, 0, 0, 0, 0), newMysqlTime(2016, 1, 1, 23, 59, 59, 999999), 0},
+		{newMysqlTime(2016, 3, 1, 0, 0, 0, 0), newMysqlTime(2016, 2, 28, 0, 0, 0, 0), 2},
+		{newMysqlTime(2004, 5, 21, 0, 0, 0, 0), newMysqlTime(2004, 1, 2, 0, 0, 0, 0), 140},
+		{newMysqlTime(1, 1, 1, 0, 0, 0, 0), newMysqlTime(9999, 12, 31, 0, 0, 0, 0), -3652058},
+	}
+
+	for i, t := range tbl {
+		c.Assert(DateDiff(t.t1, t.t2), Equals, t.expect, Commentf("%d failed.", i))
+		c.Assert(DateDiff(t.t2, t.t1), Equals, -t.expect, Commentf("%d failed.", i))
+	}
+}
+
+func (s *testMyTimeSuite) TestYearWeekAdjacentYear(c *C) {
+	// Expected values are from MySQL YEARWEEK(date, mode) for mode 4 to 7. Modes 4 and 6 start
+	// weeks on Sunday and count the week with 4 or more days in the year as week 1, modes 5
+	// and 7 start weeks on Monday and count the week with the first Monday as week 1.
+	tbl := []struct {
+		t      mysqlTime
+		expect [4]int
+	}{
+		{newMysqlTime(2014, 12, 31, 0, 0, 0, 0), [4]int{201453, 201452, 201453, 201452}},
+		{newMysqlTime(2015, 12, 31, 0, 0, 0, 0), [4]int{201552, 201552, 201552, 201552}},
+		{newMysqlTime(2016, 1, 1, 0, 0, 0, 0), [4]int{201552, 201552, 201552, 201552}},
+		{newMysqlTime(2016, 12, 31, 0, 0, 0, 0), [4]int{201652, 201652, 201652, 201652}},
+		{newMysqlTime(2017, 1, 1, 0, 0, 0, 0), [4]int{201701, 201652, 201701, 201652}},
+		{newMysqlTime(2017, 1, 2, 0, 0, 0, 0), [4]int{201701, 201701, 201701, 201701}},
+		{newMysqlTime(2019, 12, 29, 0, 0, 0, 0), [4]int{202001, 201951, 202001, 201951}},
+		{newMysqlTime(2019, 12, 30, 0, 0, 0, 0), [4]int{202001, 201952, 202001, 201952}},
+		{newMysqlTime(2020, 12, 31, 0, 0, 0, 0), [4]int{202053, 202052, 202053, 202052}},
+		{newMysqlTime(2021, 1, 1, 0, 0, 0, 0), [4]int{202053, 202052, 202053, 202052}},
+	}
+
+	for i, t := range tbl {
+		for mode := 4; mode < 8; mode++ {
+			c.Assert(t.t.YearWeekInt(mode), Equals, t.expect[mode-4], Commentf("%d mode %d failed.", i, mode))
+		}
+	}
+}
+
+func (s *testMyTimeSuite) TestConvertTo(c *C) {
+	defer func(f func() gotime.Time) { nowFunc = f }(nowFunc)
+	setToday := func(year, month, day, hour int) {
+		nowFunc = func() gotime.Time { return gotime.Date(year, gotime.Month(month), day, hour, 0, 0, 0, gotime.Local) }
+	}
+	setToday(2016, 12, 31, 12)
+	tbl := []struct {
+		t         mysqlTime
+		kind      TimeKind
+		fsp       int
+		expect    mysqlTime
+		truncated bool
+	}{
+		// The time part is dropped for DATE without rounding.
+		{newMysqlTime(2016, 12, 31, 23, 59, 59, 999999), TimeKindDate, 0, newMysqlTime(2016, 12, 31, 0, 0, 0, 0), true},
+		{newMysqlTime(2016, 12, 31, 12, 30, 0, 500000), TimeKindDate, 6, newMysqlTime(2016, 12, 31, 0, 0, 0, 0), true},
+		{newMysqlTime(2016, 12, 31, 0, 0, 0, 0), TimeKindDate, 0, newMysqlTime(2016, 12, 31, 0, 0, 0, 0), false},
+		// The microsecond is rounded for DATETIME.
+		{newMysqlTime(2016, 12, 31, 23, 59, 59, 999999), TimeKindDatetime, 0, newMysqlTime(2017, 1, 1, 0, 0, 0, 0), false},
+		{newMysqlTime(2016, 12, 31, 23, 59, 59, 499999), TimeKindDatetime, 0, newMysqlTime(2016, 12, 31, 23, 59, 59, 0), false},
+		{newMysqlTime(2016, 12, 31, 23, 59, 59, 123456), TimeKindDatetime, 3, newMysqlTime(2016, 12, 31, 23, 59, 59, 123000).withFsp(3), false},
+		{newMysqlTime(2016, 12, 31, 23, 59, 59, 123456), TimeKindDatetime, 6, newMysqlTime(2016, 12, 31, 23, 59, 59, 123456).withFsp(6), false},
+		// The date part is dropped for TIME and the microsecond is rounded.
+		{newMysqlTime(2016, 12, 31, 12, 30, 59, 500000), TimeKindTime, 0, newMysqlTime(0, 0, 0, 12, 31, 0, 0), false},
+		{SecToTime(-3600, -500000), TimeKindTime, 0, SecToTime(-3601, 0), false},
+		// A TIME value is converted on the current date.
+		{newMysqlTime(0, 0, 0, 25, 0, 0, 500000), TimeKindDatetime, 0, newMysqlTime(2017, 1, 1, 1, 0, 1, 0), false},
+		{SecToTime(-3600, 0), TimeKindDate, 0, newMysqlTime(2016, 12, 30, 0, 0, 0, 0), false},
+		{newMysqlTime(0, 0, 0, 12, 30, 0, 0), TimeKindDate, 0, newMysqlTime(2016, 12, 31, 0, 0, 0, 0), false},
+	}
+
+	for i, t := range tbl {
+		v, warnings, err := t.t.ConvertTo(t.kind, t.fsp)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(v, Equals, t.expect, Commentf("%d failed.", i))
+		if t.truncated {
+			c.Assert(warnings, HasLen, 1, Commentf("%d failed.", i))
+			c.Assert(terror.ErrorEqual(warnings[0], ErrTruncated), IsTrue, Commentf("%d failed.", i))
+		} else {
+			c.Assert(warnings, HasLen, 0, Commentf("%d failed.", i))
+		}
+	}
+
+	_, _, err := newMysqlTime(9999, 12, 31, 23, 59, 59, 999999).ConvertTo(TimeKindDatetime, 0)
+	c.Assert(err, NotNil)
+	_, _, err = newMysqlTime(2016, 1, 1, 0, 0, 0, 0).ConvertTo(TimeKindDatetime, 7)
+	c.Assert(err, NotNil)
+	setToday(9999, 12, 31, 0)
+	_, _, err = newMysqlTime(0, 0, 0, 25, 0, 0, 0).ConvertTo(TimeKindDatetime, 0)
+	c.Assert(terror.ErrorEqual(err, ErrDatetimeOutOfRange), IsTrue)
+
+	// The TIME value is placed on the date of today, the time part of today is ignored.
+	setToday(2000, 2, 29, 23)
+	v, _, err := newMysqlTime(0, 0, 0, 1, 2, 3, 0).ConvertTo(TimeKindDatetime, 0)
+	c.Assert(err, IsNil)
+	c.Assert(v, Equals, newMysqlTime(2000, 2, 29, 1, 2, 3, 0))
+}
+
+func (s *testMyTimeSuite) TestAddGoDuration(c *C) {
+	tbl := []struct {
+		t      mysqlTime
+		d      gotime.Duration
+		expect mysqlTime
+	}{
+		{newMysqlTime(2016, 12, 31, 0, 0, 0, 0), 25 * gotime.Hour, newMysqlTime(2017, 1, 1, 1, 0, 0, 0)},
+		{newMysqlTime(2017, 1, 1, 0, 0, 0, 0), -gotime.Microsecond, newMysqlTime(2016, 12, 31, 23, 59, 59, 999999)},
+		{newMysqlTime(2016, 2, 28, 23, 0, 0, 0), 2 * gotime.Hour, newMysqlTime(2016, 2, 29, 1, 0, 0, 0)},
+		{newMysqlTime(2016, 3, 1, 0, 0, 0, 0), -25 * gotime.Hour, newMysqlTime(2016, 2, 28, 23, 0, 0, 0)},
+		{newMysqlTime(2016, 12, 31, 23, 59, 59, 999999), 1999 * gotime.Nanosecond, newMysqlTime(2017, 1, 1, 0, 0, 0, 0)},
+		{newMysqlTime(2016, 1, 1, 0, 0, 0, 0), -1999 * gotime.Nanosecond, newMysqlTime(2015, 12, 31, 23, 59, 59, 999999)},
+		{newMysqlTime(2016, 1, 1, 0, 0, 0, 0), 999 * gotime.Nanosecond, newMysqlTime(2016, 1, 1, 0, 0, 0, 0)},
+		{newMysqlTime(1, 1, 1, 0, 0, 0, 0), 290 * 365 * 24 * gotime.Hour, newMysqlTime(290, 10, 23, 0, 0, 0, 0)},
+	}
+
+	for i, t := range tbl {
+		v, err := t.t.AddGoDuration(t.d)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(v, Equals, t.expect, Commentf("%d failed.", i))
+	}
+
+	_, err := newMysqlTime(9999, 12, 31, 23, 59, 59, 999999).AddGoDuration(gotime.Microsecond)
+	c.Assert(err, NotNil)
+	_, err = newMysqlTime(1, 1, 1, 0, 0, 0, 0).AddGoDuration(-gotime.Microsecond)
+	c.Assert(err, NotNil)
+	_, err = ZeroTime.AddGoDuration(gotime.Hour)
+	c.Assert(err, NotNil)
+}
+
+func (s *testMyTimeSuite) TestGoTimeError(c *C) {
+	newYork, err := gotime.LoadLocation("America/New_York")
+	c.Assert(err, IsNil)
+
+	tbl := []struct {
+		t      mysqlTime
+		loc    *gotime.Location
+		expect error
+	}{
+		{SecToTime(-3600, 0), gotime.UTC, ErrTruncatedWrongValue},
+		{newMysqlTime(0, 0, 0, 24, 0, 0, 0), gotime.UTC, ErrDatetimeOutOfRange},
+		{newMysqlTime(2016, 12, 31, 24, 0, 0, 0), gotime.UTC, ErrDatetimeOutOfRange},
+		{newMysqlTime(2016, 12, 31, 23, 60, 0, 0), gotime.UTC, ErrDatetimeOutOfRange},
+		{newMysqlTime(2015, 2, 29, 0, 0, 0, 0), gotime.UTC, ErrDatetimeOutOfRange},
+		{newMysqlTime(2016, 13, 1, 0, 0, 0, 0), gotime.UTC, ErrDatetimeOutOfRange},
+		{ZeroTime, gotime.UTC, ErrZeroDateNotAllowed},
+		{newMysqlTime(2016, 12, 0, 0, 0, 0, 0), gotime.UTC, ErrZeroDateNotAllowed},
+		{newMysqlTime(2016, 0, 1, 0, 0, 0, 0), gotime.UTC, ErrZeroDateNotAllowed},
+		// 02:30 is skipped when the daylight saving time starts.
+		{newMysqlTime(2016, 3, 13, 2, 30, 0, 0), newYork, ErrTruncatedWrongValue},
+	}
+
+	for i, t := range tbl {
+		_, err := t.t.GoTime(t.loc)
+		c.Assert(terror.ErrorEqual(err, t.expect), IsTrue, Commentf("%d failed.", i))
+	}
+
+	_, err = newMysqlTime(2016, 3, 13, 3, 30, 0, 0).GoTime(newYork)
+	c.Assert(err, IsNil)
+
+	// The errors are mapped to MySQL error codes for the warnings.
+	c.Assert(ErrDatetimeOutOfRange.ToSQLError().Code, Equals, uint16(mysql.ErrDatetimeFunctionOverflow))
+	c.Assert(ErrZeroDateNotAllowed.ToSQLError().Code, Equals, uint16(mysql.ErrWrongValue))
+	c.Assert(ErrTruncatedWrongValue.ToSQLError().Code, Equals, uint16(mysql.ErrTruncatedWrongValue))
+}
+
+func (s *testMyTimeSuite) TestTruncTo(c *C) {
+	t := newMysqlTime(2016, 8, 15, 10, 20, 30, 123456)
+	tbl := []struct {
+		t      mysqlTime
+		unit   string
+		expect mysqlTime
+	}{
+		{t, "YEAR", newMysqlTime(2016, 1, 1, 0, 0, 0, 0)},
+		{t, "QUARTER", newMysqlTime(2016, 7, 1, 0, 0, 0, 0)},
+		{t, "MONTH", newMysqlTime(2016, 8, 1, 0, 0, 0, 0)},
+		{t, "DAY", newMysqlTime(2016, 8, 15, 0, 0, 0, 0)},
+		{t, "HOUR", newMysqlTime(2016, 8, 15, 10, 0, 0, 0)},
+		{t, "MINUTE", newMysqlTime(2016, 8, 15, 10, 20, 0, 0)},
+		{t, "SECOND", newMysqlTime(2016, 8, 15, 10, 20, 30, 0)},
+		{t, "month", newMysqlTime(2016, 8, 1, 0, 0, 0, 0)},
+		{newMysqlTime(2016, 3, 15, 10, 20, 30, 0), "MONTH", newMysqlTime(2016, 3, 1, 0, 0, 0, 0)},
+		{newMysqlTime(2016, 3, 31, 23, 59, 59, 999999), "QUARTER", newMysqlTime(2016, 1, 1, 0, 0, 0, 0)},
+		{newMysqlTime(2016, 4, 1, 0, 0, 0, 0), "QUARTER", newMysqlTime(2016, 4, 1, 0, 0, 0, 0)},
+		{newMysqlTime(2016, 12, 31, 23, 59, 59, 999999), "QUARTER", newMysqlTime(2016, 10, 1, 0, 0, 0, 0)},
+		{newMysqlTime(2016, 12, 31, 23, 59, 59, 999999).withFsp(6), "SECOND", newMysqlTime(2016, 12, 31, 23, 59, 59, 0).withFsp(6)},
+	}
+
+	for i, t := range tbl {
+		v, err := t.t.TruncTo(t.unit)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(v, Equals, t.expect, Commentf("%d failed.", i))
+	}
+
+	_, err := t.TruncTo("WEEK")
+	c.Assert(err, NotNil)
+	_, err = t.TruncTo("DAY_HOUR")
+	c.Assert(err, NotNil)
+	_, err = ZeroTime.TruncTo("YEAR")
+	c.Assert(err, NotNil)
+}
+
+func (s *testMyTimeSuite) TestQuarterStart(c *C) {
+	tbl := []struct {
+		t      mysqlTime
+		expect mysqlTime
+	}{
+		{newMysqlTime(2016, 1, 1, 0, 0, 0, 0), newMysqlTime(2016, 1, 1, 0, 0, 0, 0)},
+		{newMysqlTime(2016, 2, 29, 12, 30, 0, 0), newMysqlTime(2016, 1, 1, 0, 0, 0, 0)},
+		{newMysqlTime(2016, 3, 31, 23, 59, 59, 999999), newMysqlTime(2016, 1, 1, 0, 0, 0, 0)},
+		{newMysqlTime(2016, 4, 1, 0, 0, 0, 1), newMysqlTime(2016, 4, 1, 0, 0, 0, 0)},
+		{newMysqlTime(2016, 6, 30, 23, 59, 59, 0), newMysqlTime(2016, 4, 1, 0, 0, 0, 0)},
+		{newMysqlTime(2016, 8, 15, 10, 20, 30, 0), newMysqlTime(2016, 7, 1, 0, 0, 0, 0)},
+		{newMysqlTime(2016, 12, 31, 23, 59, 59, 999999), newMysqlTime(2016, 10, 1, 0, 0, 0, 0)},
+		{newMysqlTime(2016, 11, 0, 0, 0, 0, 0), newMysqlTime(2016, 10, 1, 0, 0, 0, 0)},
+		{newMysqlTime(2016, 0, 0, 0, 0, 0, 0), ZeroTime},
+		{ZeroTime, ZeroTime},
+	}
+
+	for i, t := range tbl {
+		c.Assert(t.t.QuarterStart(), Equals, t.expect, Commentf("%d failed.", i))
+		if t.t.day != 0 {
+			v, err := t.t.TruncTo("QUARTER")
+			c.Assert(err, IsNil, Commentf("%d failed.", i))
+			c.Assert(v, Equals, t.expect, Commentf("%d failed.", i))
+		}
+	}
+}
+
+func (s *testMyTimeSuite) TestDateRange(c *C) {
+	tbl := []struct {
+		start  mysqlTime
+		end    mysqlTime
+		unit   string
+		n      int
+		expect []mysqlTime
+	}{
+		{newMysqlTime(2016, 2, 27, 0, 0, 0, 0), newMysqlTime(2016, 3, 1, 0, 0, 0, 0), "DAY", 1, []mysqlTime{
+			newMysqlTime(2016, 2, 27, 0, 0, 0, 0),
+			newMysqlTime(2016, 2, 28, 0, 0, 0, 0),
+			newMysqlTime(2016, 2, 29, 0, 0, 0, 0),
+			newMysqlTime(2016, 3, 1, 0, 0, 0, 0),
+		}},
+		{newMysqlTime(2016, 12, 30, 0, 0, 0, 0), newMysqlTime(2017, 1, 3, 12, 0, 0, 0), "DAY", 2, []mysqlTime{
+			newMysqlTime(2016, 12, 30, 0, 0, 0, 0),
+			newMysqlTime(2017, 1, 1, 0, 0, 0, 0),
+			newMysqlTime(2017, 1, 3, 0, 0, 0, 0),
+		}},
+		// The clamped day doesn't drift the following months.
+		{newMysqlTime(2016, 1, 31, 0, 0, 0, 0), newMysqlTime(2016, 5, 15, 0, 0, 0, 0), "MONTH", 1, []mysqlTime{
+			newMysqlTime(2016, 1, 31, 0, 0, 0, 0),
+			newMysqlTime(2016, 2, 29, 0, 0, 0, 0),
+			newMysqlTime(2016, 3, 31, 0, 0, 0, 0),
+			newMysqlTime(2016, 4, 30, 0, 0, 0, 0),
+		}},
+		{newMysqlTime(2016, 5, 31, 0, 0, 0, 0), newMysqlTime(2016, 2, 1, 0, 0, 0, 0), "MONTH", -1, []mysqlTime{
+			newMysqlTime(2016, 5, 31, 0, 0, 0, 0),
+			newMysqlTime(2016, 4, 30, 0, 0, 0, 0),
+			newMysqlTime(2016, 3, 31, 0, 0, 0, 0),
+			newMysqlTime(2016, 2, 29, 0, 0, 0, 0),
+		}},
+		{newMysqlTime(2016, 1, 1, 0, 0, 0, 0), newMysqlTime(2016, 1, 1, 0, 0, 0, 0), "DAY", 1, []mysqlTime{
+			newMysqlTime(2016, 1, 1, 0, 0, 0, 0),
+		}},
+		// The walk stops at the end of the supported range.
+		{newMysqlTime(9999, 12, 30, 0, 0, 0, 0), newMysqlTime(9999, 12, 31, 0, 0, 0, 0), "MONTH", 1, []mysqlTime{
+			newMysqlTime(9999, 12, 30, 0, 0, 0, 0),
+		}},
+	}
+
+	for i, t := range tbl {
+		dates, err := DateRange(t.start, t.end, t.unit, t.n)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(dates, DeepEquals, t.expect, Commentf("%d failed.", i))
+	}
+
+	errTbl := []struct {
+		start mysqlTime
+		end   mysqlTime
+		unit  string
+		n     int
+	}{
+		{newMysqlTime(2016, 1, 1, 0, 0, 0, 0), newMysqlTime(2016, 2, 1, 0, 0, 0, 0), "DAY", 0},
+		{newMysqlTime(2016, 1, 1, 0, 0, 0, 0), newMysqlTime(2016, 2, 1, 0, 0, 0, 0), "DAY", -1},
+		{newMysqlTime(2016, 2, 1, 0, 0, 0, 0), newMysqlTime(2016, 1, 1, 0, 0, 0, 0), "MONTH", 1},
+		{newMysqlTime(2016, 1, 1, 0, 0, 0, 0), newMysqlTime(2016, 2, 1, 0, 0, 0, 0), "DAY_HOUR", 1},
+		{ZeroTime, newMysqlTime(2016, 2, 1, 0, 0, 0, 0), "DAY", 1},
+		// Too many dates.
+		{newMysqlTime(1, 1, 1, 0, 0, 0, 0), newMysqlTime(9999, 12, 31, 0, 0, 0, 0), "MICROSECOND", 1},
+	}
+	for i, t := range errTbl {
+		_, err := DateRange(t.start, t.end, t.unit, t.n)
+		c.Assert(err, NotNil, Commentf("%d failed.", i))
+	}
+
+	// The dates at the limit are still returned.
+	start := newMysqlTime(2016, 1, 1, 0, 0, 0, 0)
+	end, err := start.AddInterval("SECOND", maxDateRangeLen-1)
+	c.Assert(err, IsNil)
+	dates, err := DateRange(start, end, "SECOND", 1)
+	c.Assert(err, IsNil)
+	c.Assert(dates, HasLen, maxDateRangeLen)
+	end, err = start.AddInterval("SECOND", maxDateRangeLen)
+	c.Assert(err, IsNil)
+	_, err = DateRange(start, end, "SECOND", 1)
+	c.Assert(err, NotNil)
+}
+
+func (s *testMyTimeSuite) TestDayNumber(c *C) {
+	tbl := []struct {
+		year   int
+		month  int
+		day    int
+		expect int
+	}{
+		{0, 1, 1, 1},
+		{1, 1, 1, 366},
+		{2016, 2, 29, 736388},
+		{2016, 3, 1, 736389},
+		{2016, 12, 31, 736694},
+		{9999, 12, 31, 3652424},
+	}
+
+	for i, t := range tbl {
+		n, err := DayNumber(t.year, t.month, t.day)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(n, Equals, t.expect, Commentf("%d failed.", i))
+		c.Assert(n, Equals, calcDaynr(t.year, t.month, t.day), Commentf("%d failed.", i))
+	}
+
+	errTbl := []struct {
+		year  int
+		month int
+		day   int
+	}{
+		{2016, 13, 1},
+		{2016, 1, 32},
+		{2016, 0, 1},
+		{2016, 1, 0},
+		{0, 0, 0},
+		{2017, 2, 29},
+		{2016, 4, 31},
+		{-1, 1, 1},
+		{10000, 1, 1},
+	}
+	for i, t := range errTbl {
+		_, err := DayNumber(t.year, t.month, t.day)
+		c.Assert(terror.ErrorEqual(err, ErrInvalidTimeFormat), IsTrue, Commentf("%d failed.", i))
+	}
+}
+
+func (s *testMyTimeSuite) TestIsValidDate(c *C) {
+	tbl := []struct {
+		year   int
+		month  int
+		day    int
+		expect bool
+	}{
+		{2016, 2, 29, true},
+		{2017, 2, 29, false},
+		{2017, 2, 28, true},
+		{2000, 2, 29, true},
+		{1900, 2, 29, false},
+		{2016, 4, 31, false},
+		{2016, 4, 30, true},
+		{2016, 12, 31, true},
+		{2016, 1, 32, false},
+		{2016, 13, 1, false},
+		{2016, 0, 1, false},
+		{2016, 1, 0, false},
+		{0, 0, 0, false},
+		{0, 1, 1, true},
+	}
+
+	for i, t := range tbl {
+		c.Assert(IsValidDate(t.year, t.month, t.day), Equals, t.expect, Commentf("%d failed.", i))
+	}
+}
+
+func (s *testMyTimeSuite) TestIsValidTimeOfDay(c *C) {
+	tbl := []struct {
+		hour        int
+		minute      int
+		second      int
+		microsecond int
+		expect      bool
+	}{
+		{0, 0, 0, 0, true},
+		{23, 59, 59, 999999, true},
+		{24, 0, 0, 0, false},
+		{-1, 0, 0, 0, false},
+		{12, 60, 0, 0, false},
+		{12, -1, 0, 0, false},
+		{12, 0, 60, 0, false},
+		{12, 0, -1, 0, false},
+		{12, 0, 0, 1000000, false},
+		{12, 0, 0, -1, false},
+	}
+
+	for i, t := range tbl {
+		c.Assert(IsValidTimeOfDay(t.hour, t.minute, t.second, t.microsecond), Equals, t.expect, Commentf("%d failed.", i))
+	}
+}
+
+func (s *testMyTimeSuite) TestPackDatetimeV2(c *C) {
+	// Values are in ascending order.
+	values := []mysqlTime{
+		ZeroTime,
+		newMysqlTime(0, 0, 0, 0, 0, 0, 1),
+		newMysqlTime(1, 1, 1, 0, 0, 0, 0),
+		newMysqlTime(2016, 0, 0, 0, 0, 0, 0),
+		newMysqlTime(2016, 2, 29, 12, 30, 45, 123456),
+		newMysqlTime(2016, 12, 31, 23, 59, 59, 999998),
+		newMysqlTime(2016, 12, 31, 23, 59, 59, 999999),
+		newMysqlTime(2017, 1, 1, 0, 0, 0, 0),
+		newMysqlTime(9999, 12, 31, 23, 59, 59, 999999),
+	}
+
+	encoded := make([][]byte, 0, len(values))
+	for i, t := range values {
+		v := t.PackDatetimeV2()
+		c.Assert(v >= 0, IsTrue, Commentf("%d failed.", i))
+		result, err := UnpackDatetimeV2(v)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(result, Equals, t, Commentf("%d failed.", i))
+		data := make([]byte, 8)
+		binary.BigEndian.PutUint64(data, uint64(v))
+		encoded = append(encoded, data)
+	}
+	for i := range values {
+		for j := range values {
+			c.Assert(bytes.Compare(encoded[i], encoded[j]), Equals, Compare(values[i], values[j]), Commentf("%d %d failed.", i, j))
+		}
+	}
+
+	// Known vectors calculated by TIME_to_longlong_datetime_packed of MySQL.
+	c.Assert(newMysqlTime(2016, 12, 31, 23, 59, 59, 999999).PackDatetimeV2(), Equals, int64(0x199b3f7efb0f423f))
+	// The on-disk integer part of DATETIME2 '2012-06-21 15:55:33' is 99 8C AA FD E1.
+	c.Assert(newMysqlTime(2012, 6, 21, 15, 55, 33, 0).PackDatetimeV2()>>24+0x8000000000, Equals, int64(0x998caafde1))
+
+	errTbl := []int64{
+		-1,
+		newMysqlTime(2016, 2, 30, 0, 0, 0, 0).PackDatetimeV2(),
+		newMysqlTime(2016, 12, 31, 24, 0, 0, 0).PackDatetimeV2(),
+		newMysqlTime(2016, 12, 31, 23, 60, 0, 0).PackDatetimeV2(),
+		newMysqlTime(2016, 12, 31, 23, 59, 60, 0).PackDatetimeV2(),
+		newMysqlTime(2016, 12, 31, 0, 0, 0, 0).PackDatetimeV2() + 1000000,
+		newMysqlTime(10000, 1, 1, 0, 0, 0, 0).PackDatetimeV2(),
+	}
+	for i, v := range errTbl {
+		_, err := UnpackDatetimeV2(v)
+		c.Assert(err, NotNil, Commentf("%d failed.", i))
+	}
+}
+
+func (s *testMyTimeSuite) TestCompareToInt(c *C) {
+	tbl := []struct {
+		t      mysqlTime
+		n      int64
+		expect int
+	}{
+		{newMysqlTime(2016, 1, 1, 0, 0, 0, 0), 20160101000000, 0},
+		{newMysqlTime(2016, 1, 1, 0, 0, 0, 0), 20160101, 1},
+		{newMysqlTime(2016, 1, 1, 0, 0, 0, 0), 20160101000001, -1},
+		{newMysqlTime(2016, 1, 1, 0, 0, 1, 0), 20160101000000, 1},
+		{newMysqlTime(2016, 12, 31, 23, 59, 59, 0), 20161231235959, 0},
+		// The microsecond is ignored.
+		{newMysqlTime(2016, 1, 1, 0, 0, 0, 500000), 20160101000000, 0},
+		{newMysqlTime(2016, 12, 31, 23, 59, 59, 999999), 20161231235959, 0},
+		{ZeroTime, 0, 0},
+		{ZeroTime, -1, 1},
+		{newMysqlTime(0, 0, 0, 12, 30, 0, 0), 123000, 0},
+		{newMysqlTime(0, 0, 0, 838, 59, 59, 0), 8385959, 0},
+		{SecToTime(-3600, 0), -10000, 0},
+		{SecToTime(-3600, 0), 0, -1},
+		{newMysqlTime(9999, 12, 31, 23, 59, 59, 0), math.MaxInt64, -1},
+		{newMysqlTime(1, 1, 1, 0, 0, 0, 0), math.MinInt64, 1},
+	}
+
+	for i, t := range tbl {
+		c.Assert(t.t.CompareToInt(t.n), Equals, t.expect, Commentf("%d failed.", i))
+	}
+}
+
+func (s *testMyTimeSuite) TestGoTimeUTC(c *C) {
+	// GoTime checks the fields in UTC, and compares them with the result in other locations,
+	// both ways must agree on every value.
+	tbl := []mysqlTime{
+		newMysqlTime(2016, 12, 31, 23, 59, 59, 999999),
+		newMysqlTime(2016, 2, 29, 0, 0, 0, 0),
+		newMysqlTime(2015, 2, 29, 0, 0, 0, 0),
+		newMysqlTime(0, 1, 1, 0, 0, 0, 0),
+		newMysqlTime(0, 2, 29, 0, 0, 0, 0),
+		newMysqlTime(0, 2, 29, 23, 59, 59, 0),
+		newMysqlTime(0, 2, 30, 0, 0, 0, 0),
+		newMysqlTime(1, 1, 1, 0, 0, 0, 0),
+		newMysqlTime(9999, 12, 31, 23, 59, 59, 999999),
+		newMysqlTime(10000, 1, 1, 0, 0, 0, 0),
+		newMysqlTime(2016, 13, 1, 0, 0, 0, 0),
+		newMysqlTime(2016, 1, 32, 0, 0, 0, 0),
+		newMysqlTime(2016, 1, 1, 0, 60, 0, 0),
+		newMysqlTime(2016, 1, 1, 0, 0, 60, 0),
+		newMysqlTime(2016, 1, 1, 0, 0, 0, 1000000),
+		newMysqlTime(2016, 3, 13, 2, 30, 0, 0),
+		newMysqlTime(2016, 12, 0, 0, 0, 0, 0),
+		ZeroTime,
+	}
+
+	newYork, err := gotime.LoadLocation("America/New_York")
+	c.Assert(err, IsNil)
+	out := make([]gotime.Time, 1)
+	for i, t := range tbl {
+		tm, err := t.GoTime(gotime.UTC)
+		c.Assert(tm, DeepEquals, t.GoTimeClamped(gotime.UTC), Commentf("%d failed.", i))
+		if t.sameAsGoTime(tm) {
+			c.Assert(err, IsNil, Commentf("%d failed.", i))
+		} else {
+			c.Assert(terror.ErrorEqual(err, t.goTimeError()), IsTrue, Commentf("%d failed.", i))
+		}
+		// GoTimeBatch compares the fields in UTC too.
+		_, batchErr := GoTimeBatch([]mysqlTime{t}, gotime.UTC, out)
+		c.Assert(batchErr == nil, Equals, err == nil, Commentf("%d failed.", i))
+		// None of the values is in a daylight saving gap of New York except 2016-03-13 02:30:00.
+		if t != newMysqlTime(2016, 3, 13, 2, 30, 0, 0) {
+			_, localErr := t.GoTime(newYork)
+			c.Assert(localErr == nil, Equals, err == nil, Commentf("%d failed.", i))
+		}
+	}
+
+	// The time skipped in New York is still rejected there.
+	_, err = newMysqlTime(2016, 3, 13, 2, 30, 0, 0).GoTime(newYork)
+	c.Assert(err, NotNil)
+
+	// Values from the parsers and the checked constructor are plain values, so == works.
+	v, err := NewMysqlTimeChecked(2016, 1, 31, 12, 0, 0, 0)
+	c.Assert(err, IsNil)
+	c.Assert(v == newMysqlTime(2016, 1, 31, 12, 0, 0, 0), IsTrue)
+}
+
+func (s *testMyTimeSuite) TestAddIntervalOutOfRange(c *C) {
+	tbl := []struct {
+		t      mysqlTime
+		unit   string
+		amount int
+	}{
+		{newMysqlTime(9999, 12, 31, 0, 0, 0, 0), "DAY", 1},
+		{newMysqlTime(9999, 12, 31, 23, 59, 59, 999999), "MICROSECOND", 1},
+		{newMysqlTime(9999, 12, 31, 23, 0, 0, 0), "HOUR", 1},
+		{newMysqlTime(9999, 12, 1, 0, 0, 0, 0), "MONTH", 1},
+		{newMysqlTime(9999, 1, 1, 0, 0, 0, 0), "YEAR", 1},
+		{newMysqlTime(9999, 10, 1, 0, 0, 0, 0), "QUARTER", 1},
+		{newMysqlTime(9999, 12, 25, 0, 0, 0, 0), "WEEK", 1},
+		{newMysqlTime(1, 1, 1, 0, 0, 0, 0), "DAY", -1},
+		{newMysqlTime(1, 1, 1, 0, 0, 0, 0), "SECOND", -1},
+		{newMysqlTime(1, 1, 1, 0, 0, 0, 0), "MONTH", -13},
+		{newMysqlTime(1, 1, 1, 0, 0, 0, 0), "YEAR", -2},
+	}
+
+	for i, t := range tbl {
+		_, err := t.t.AddInterval(t.unit, t.amount)
+		c.Assert(terror.ErrorEqual(err, ErrDatetimeOutOfRange), IsTrue, Commentf("%d failed.", i))
+	}
+
+	// The edges are still in range.
+	v, err := newMysqlTime(9999, 12, 30, 0, 0, 0, 0).AddInterval("DAY", 1)
+	c.Assert(err, IsNil)
+	c.Assert(v, Equals, newMysqlTime(9999, 12, 31, 0, 0, 0, 0))
+	v, err = newMysqlTime(1, 1, 2, 0, 0, 0, 0).AddInterval("DAY", -1)
+	c.Assert(err, IsNil)
+	c.Assert(v, Equals, newMysqlTime(1, 1, 1, 0, 0, 0, 0))
+
+	// Invalid input is an error.
+	_, err = ZeroTime.AddInterval("DAY", 1)
+	c.Assert(terror.ErrorEqual(err, ErrInvalidTimeFormat), IsTrue)
+	_, err = newMysqlTime(2016, 1, 1, 0, 0, 0, 0).AddInterval("DAY_HOUR", 1)
+	c.Assert(err, NotNil)
+	c.Assert(terror.ErrorEqual(err, ErrDatetimeOutOfRange), IsFalse)
+}
+
+func (s *testMyTimeSuite) TestYearZero(c *C) {
+	// Year 0 is not a leap year, so it has 365 days and 0000-01-01 is day number 1.
+	next := newMysqlTime(1, 1, 1, 0, 0, 0, 0)
+	i := 0
+	for month := 1; month <= 12; month++ {
+		for day := 1; day <= lastDayOfMonth(0, month); day++ {
+			i++
+			t := newMysqlTime(0, month, day, 0, 0, 0, 0)
+			c.Assert(calcDaynr(0, month, day), Equals, i, Commentf("%d failed.", i))
+			c.Assert(ToDays(t), Equals, int64(i), Commentf("%d failed.", i))
+			c.Assert(t.YearDay(), Equals, i, Commentf("%d failed.", i))
+			c.Assert(DaysBetween(t, next), Equals, 366-i, Commentf("%d failed.", i))
+			c.Assert(t.DayOfWeek(), Equals, calcWeekday(i, true)+1, Commentf("%d failed.", i))
+			for mode := 0; mode < 8; mode++ {
+				week := t.Week(mode)
+				c.Assert(week >= 0 && week <= 53, IsTrue, Commentf("%d mode %d failed.", i, mode))
+			}
+		}
+	}
+	c.Assert(i, Equals, 365)
+	c.Assert(IsValidDate(0, 2, 29), IsFalse)
+
+	// Like MySQL, 0000-01-01 is a Sunday, and in mode 3 it belongs to the last week of year -1.
+	t := newMysqlTime(0, 1, 1, 0, 0, 0, 0)
+	c.Assert(t.Weekday(), Equals, gotime.Sunday)
+	year, week := t.YearWeek(3)
+	c.Assert(year, Equals, -1)
+	c.Assert(week, Equals, 52)
+
+	// Dates before 0001-01-01 are out of the range of date arithmetic.
+	_, err := t.addDateTime(1, 0, 0)
+	c.Assert(terror.ErrorEqual(err, ErrDatetimeOutOfRange), IsTrue)
+}
+
+func (s *testMyTimeSuite) TestDaysInMonth(c *C) {
+	tbl := []struct {
+		t      mysqlTime
+		expect int
+	}{
+		{newMysqlTime(2016, 1, 15, 0, 0, 0, 0), 31},
+		{newMysqlTime(2016, 2, 15, 0, 0, 0, 0), 29},
+		{newMysqlTime(2017, 2, 15, 0, 0, 0, 0), 28},
+		{newMysqlTime(2000, 2, 1, 0, 0, 0, 0), 29},
+		{newMysqlTime(1900, 2, 1, 0, 0, 0, 0), 28},
+		{newMysqlTime(0, 2, 1, 0, 0, 0, 0), 28},
+		{newMysqlTime(2016, 4, 0, 0, 0, 0, 0), 30},
+		{newMysqlTime(2016, 12, 31, 0, 0, 0, 0), 31},
+		{newMysqlTime(2016, 0, 0, 0, 0, 0, 0), 0},
+		{ZeroTime, 0},
+	}
+
+	for i, t := range tbl {
+		c.Assert(t.t.DaysInMonth(), Equals, t.expect, Commentf("%d failed.", i))
+	}
+}
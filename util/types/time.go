@@ -92,9 +92,9 @@ var (
 
 var (
 	// minDatetime is the minimum for mysql datetime type.
-	minDatetime = FromDate(1000, 1, 1, 0, 0, 0, 0)
+	minDatetime = newMysqlTime(1000, 1, 1, 0, 0, 0, 0)
 	// maxDatetime is the maximum for mysql datetime type.
-	maxDatetime = FromDate(9999, 12, 31, 23, 59, 59, 999999)
+	maxDatetime = newMysqlTime(9999, 12, 31, 23, 59, 59, 999999)
 
 	// minTimestamp is the minimum for mysql timestamp type.
 	minTimestamp = gotime.Date(1970, 1, 1, 0, 0, 1, 0, gotime.UTC)
@@ -132,10 +132,12 @@ type TimeInternal interface {
 	Minute() int
 	Second() int
 	Weekday() gotime.Weekday
+	DayOfWeek() int
 	YearDay() int
 	YearWeek(mode int) (int, int)
 	Week(mode int) int
 	Microsecond() int
+	IsNegative() bool
 	GoTime(*gotime.Location) (gotime.Time, error)
 }
 
@@ -148,8 +150,13 @@ func FromGoTime(t gotime.Time) TimeInternal {
 }
 
 // FromDate makes a internal time representation from the given date.
-func FromDate(year int, month int, day int, hour int, minute int, second int, microsecond int) TimeInternal {
-	return newMysqlTime(year, month, day, hour, minute, second, microsecond)
+// The fields are validated by NewMysqlTimeChecked, zero month or day is allowed.
+func FromDate(year int, month int, day int, hour int, minute int, second int, microsecond int) (TimeInternal, error) {
+	t, err := NewMysqlTimeChecked(year, month, day, hour, minute, second, microsecond)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return t, nil
 }
 
 // Clock returns the hour, minute, and second within the day specified by t.
@@ -169,7 +176,7 @@ type Time struct {
 
 // CurrentTime returns current time with type tp.
 func CurrentTime(tp uint8) Time {
-	return Time{Time: FromGoTime(gotime.Now()), Type: tp, Fsp: 0}
+	return Time{Time: FromGoTime(nowFunc()), Type: tp, Fsp: 0}
 }
 
 func (t Time) String() string {
@@ -193,6 +200,28 @@ func (t Time) IsZero() bool {
 	return compareTime(t.Time, ZeroTime) == 0
 }
 
+// TimeKind is the kind of a temporal value, DATETIME, DATE or TIME.
+type TimeKind int
+
+// Temporal value kinds.
+const (
+	TimeKindDatetime TimeKind = iota
+	TimeKindDate
+	TimeKindTime
+)
+
+// Kind returns the kind of t according to its Type, which is set when t is parsed or converted,
+// so the zero value 0000-00-00 is still a DATE if it's typed so.
+func (t Time) Kind() TimeKind {
+	switch t.Type {
+	case mysql.TypeDate:
+		return TimeKindDate
+	case mysql.TypeDuration:
+		return TimeKindTime
+	}
+	return TimeKindDatetime
+}
+
 const numberFormat = "%Y%m%d%H%i%s"
 const dateFormat = "%Y%m%d"
 
@@ -266,7 +295,53 @@ func (t Time) Compare(o Time) int {
 	return compareTime(t.Time, o.Time)
 }
 
+// Compare returns an integer comparing t1 to t2 field by field from year to microsecond.
+// If t1 is after t2, return 1, equal t2, return 0, before t2, return -1.
+// The zero date is before any other date.
+func Compare(t1, t2 TimeInternal) int {
+	return compareTime(t1, t2)
+}
+
+// CompareWithFsp is like Compare, but rounds both t1 and t2 to fsp first, like MySQL compares
+// a DATETIME(6) column with a DATETIME(0) value at the lower precision.
+// Invalid fsp is treated as DefaultFsp.
+func CompareWithFsp(t1, t2 TimeInternal, fsp int) int {
+	return compareTime(roundForCompare(t1, fsp), roundForCompare(t2, fsp))
+}
+
+func roundForCompare(t TimeInternal, fsp int) mysqlTime {
+	mt := newMysqlTime(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Microsecond())
+	mt.neg = t.IsNegative()
+	fsp, err := checkFsp(fsp)
+	if err != nil {
+		fsp = DefaultFsp
+	}
+	rounded, err := mt.RoundToFsp(fsp)
+	if err != nil {
+		// The carry overflows, e.g. 9999-12-31 23:59:59.5, keep it at the maximum.
+		return mt.TruncateToFsp(fsp)
+	}
+	return rounded
+}
+
 func compareTime(a, b TimeInternal) int {
+	negA, negB := a.IsNegative(), b.IsNegative()
+	switch {
+	case negA && !negB:
+		return -1
+	case !negA && negB:
+		return 1
+	}
+
+	// The magnitudes of negative TIME values are compared in reverse order.
+	res := compareTimeAbs(a, b)
+	if negA {
+		return -res
+	}
+	return res
+}
+
+func compareTimeAbs(a, b TimeInternal) int {
 	ta := datetimeToUint64(a)
 	tb := datetimeToUint64(b)
 
@@ -339,7 +414,7 @@ func (t Time) roundFrac(fsp int) (Time, error) {
 		if t2.Day()-1 > 0 {
 			return t, errors.Trace(ErrInvalidTimeFormat)
 		}
-		nt = FromDate(t.Time.Year(), t.Time.Month(), t.Time.Day(), hour, minute, second, microsecond)
+		nt = newMysqlTime(t.Time.Year(), t.Time.Month(), t.Time.Day(), hour, minute, second, microsecond)
 	}
 
 	return Time{Time: nt, Type: t.Type, Fsp: fsp}, nil
@@ -418,7 +493,7 @@ func (t *Time) FromPackedUint(packed uint64) error {
 		loc = gotime.UTC
 		t.Time = FromGoTime(gotime.Date(year, gotime.Month(month), day, hour, minute, second, microsec*1000, loc).In(local))
 	} else {
-		t.Time = FromDate(year, month, day, hour, minute, second, microsec)
+		t.Time = newMysqlTime(year, month, day, hour, minute, second, microsec)
 		if err := t.check(); err != nil {
 			return errors.Trace(err)
 		}
@@ -551,6 +626,12 @@ func parseDatetime(str string, fsp int) (Time, error) {
 	case 3:
 		// YYYY-MM-DD
 		err = scanTimeArgs(seps, &year, &month, &day)
+	case 4:
+		// YYYY-MM-DD HH
+		err = scanTimeArgs(seps, &year, &month, &day, &hour)
+	case 5:
+		// YYYY-MM-DD HH-MM
+		err = scanTimeArgs(seps, &year, &month, &day, &hour, &minute)
 	case 6:
 		// We don't have fractional seconds part.
 		// YYYY-MM-DD HH-MM-SS
@@ -579,8 +660,11 @@ func parseDatetime(str string, fsp int) (Time, error) {
 	if err != nil {
 		return ZeroDatetime, errors.Trace(err)
 	}
+	if fieldsOverflow(year, month, day, hour, minute, second, microsecond) {
+		return ZeroDatetime, errors.Trace(ErrInvalidTimeFormat)
+	}
 
-	tmp := FromDate(year, month, day, hour, minute, second, microsecond)
+	var tmp TimeInternal = newMysqlTime(year, month, day, hour, minute, second, microsecond)
 	if overflow {
 		// Convert to Go time and add 1 second, to handle input like 2017-01-05 08:40:59.575601
 		t1, err := tmp.GoTime(gotime.Local)
@@ -636,6 +720,8 @@ func ParseYear(str string) (int16, error) {
 	return y, nil
 }
 
+// adjustYear converts two digit year to four digit year, 00-69 to 2000-2069 and 70-99 to 1970-1999,
+// other years are returned untouched.
 // See https://dev.mysql.com/doc/refman/5.7/en/two-digit-years.html
 func adjustYear(y int) int {
 	if y >= 0 && y <= 69 {
@@ -718,7 +804,7 @@ func (d Duration) ToNumber() *MyDecimal {
 // ConvertToTime converts duration to Time.
 // Tp is TypeDatetime, TypeTimestamp and TypeDate.
 func (d Duration) ConvertToTime(tp uint8) (Time, error) {
-	year, month, day := gotime.Now().Date()
+	year, month, day := nowFunc().Date()
 	// just use current year, month and day.
 	n := gotime.Date(year, month, day, 0, 0, 0, 0, gotime.Local)
 	n = n.Add(d.Duration)
@@ -775,34 +861,109 @@ func (d Duration) CompareString(str string) (int, error) {
 	return d.Compare(o), nil
 }
 
-// Hour returns current hour.
-// e.g, hour("11:11:11") -> 11
+// Negative returns whether d is a negative duration.
+// The accessors like Hour return the absolute values, and the sign is reported by Negative.
+func (d Duration) Negative() bool {
+	return d.Duration < 0
+}
+
+// Hour returns current hour, it's the absolute value for negative duration.
+// e.g, hour("11:11:11") -> 11, hour("-12:30:00") -> 12
 func (d Duration) Hour() int {
 	_, hour, _, _, _ := splitDuration(d.Duration)
 	return hour
 }
 
-// Minute returns current minute.
-// e.g, hour("11:11:11") -> 11
+// Minute returns current minute, it's the absolute value for negative duration.
+// e.g, minute("11:11:11") -> 11
 func (d Duration) Minute() int {
 	_, _, minute, _, _ := splitDuration(d.Duration)
 	return minute
 }
 
-// Second returns current second.
-// e.g, hour("11:11:11") -> 11
+// Second returns current second, it's the absolute value for negative duration.
+// e.g, second("11:11:11") -> 11
 func (d Duration) Second() int {
 	_, _, _, second, _ := splitDuration(d.Duration)
 	return second
 }
 
-// MicroSecond returns current microsecond.
-// e.g, hour("11:11:11.11") -> 110000
+// MicroSecond returns current microsecond, it's the absolute value for negative duration.
+// e.g, microsecond("11:11:11.11") -> 110000
 func (d Duration) MicroSecond() int {
 	_, _, _, _, frac := splitDuration(d.Duration)
 	return frac
 }
 
+// ToSeconds returns the number of whole seconds of d, the fraction is truncated toward zero.
+// e.g, ToSeconds("-01:00:01.5") -> -3601
+func (d Duration) ToSeconds() int64 {
+	return int64(d.Duration / gotime.Second)
+}
+
+// Add adds d and v, the fsp of the result is the larger one.
+// Like MySQL, the result is clipped to the range [MinTime, MaxTime],
+// and ErrOverflow is returned with the clipped result as a warning.
+func (d Duration) Add(v Duration) (Duration, error) {
+	fsp := d.Fsp
+	if v.Fsp > fsp {
+		fsp = v.Fsp
+	}
+	sum := Duration{Duration: d.Duration + v.Duration, Fsp: fsp}
+	if sum.ClipTime() {
+		return sum, errors.Trace(ErrOverflow)
+	}
+	return sum, nil
+}
+
+// ClipTime clips d to the TIME range [MinTime, MaxTime] like MySQL does after ADDTIME and SUBTIME,
+// and returns whether d is clipped so the caller can raise a warning.
+func (d *Duration) ClipTime() (clipped bool) {
+	switch {
+	case d.Duration > MaxTime:
+		d.Duration = MaxTime
+	case d.Duration < MinTime:
+		d.Duration = MinTime
+	default:
+		return false
+	}
+	return true
+}
+
+// Sub subtracts v from d, the result is clipped like Add.
+func (d Duration) Sub(v Duration) (Duration, error) {
+	v.Duration = -v.Duration
+	return d.Add(v)
+}
+
+// durationFromSec creates a Duration from the absolute seconds and microseconds with the sign,
+// like the result of calcTimeDiff. The result is clipped to the range [MinTime, MaxTime].
+func durationFromSec(seconds, microseconds int, neg bool, fsp int) Duration {
+	d := gotime.Duration(seconds)*gotime.Second + gotime.Duration(microseconds)*gotime.Microsecond
+	if d > MaxTime {
+		d = MaxTime
+	}
+	if neg {
+		d = -d
+	}
+	return Duration{Duration: d, Fsp: fsp}
+}
+
+// DurationFromTime creates a Duration from the time part of t, the date part is ignored.
+// The hour of a TIME value may exceed 23, and the result is clipped to the range [MinTime, MaxTime].
+func DurationFromTime(t TimeInternal, fsp int) Duration {
+	seconds := t.Hour()*3600 + t.Minute()*60 + t.Second()
+	return durationFromSec(seconds, t.Microsecond(), t.IsNegative(), fsp)
+}
+
+// toMysqlTime converts d to a TIME value in mysqlTime.
+func (d Duration) toMysqlTime() mysqlTime {
+	sign, hours, minutes, seconds, fraction := splitDuration(d.Duration)
+	t := newMysqlTime(0, 0, 0, hours, minutes, seconds, fraction)
+	t.neg = sign < 0
+	return t
+}
+
 // ParseDuration parses the time form a formatted string with a fractional seconds part,
 // returns the duration type Time value.
 // See http://dev.mysql.com/doc/refman/5.7/en/fractional-seconds.html
@@ -859,15 +1020,17 @@ func ParseDuration(str string, fsp int) (Duration, error) {
 			hour, err = strconv.Atoi(seps[0])
 		} else {
 			// No delimiter.
-			if len(str) == 6 {
-				// HHMMSS
-				_, err = fmt.Sscanf(str, "%2d%2d%2d", &hour, &minute, &second)
-			} else if len(str) == 4 {
-				// MMSS
-				_, err = fmt.Sscanf(str, "%2d%2d", &minute, &second)
-			} else if len(str) == 2 {
-				// SS
-				_, err = fmt.Sscanf(str, "%2d", &second)
+			if n := len(str); n >= 1 && n <= 6 {
+				// The digits are aligned from the right like MySQL, HHMMSS, HMMSS, MMSS, MSS, SS or S,
+				// e.g. 1234 is 00:12:34.
+				var v uint64
+				if v, err = strconv.ParseUint(str, 10, 32); err != nil {
+					return ZeroDuration, errors.Trace(ErrInvalidTimeFormat)
+				}
+				hour, minute, second = int(v/10000), int(v/100%100), int(v%100)
+				if minute > 59 || second > 59 {
+					return ZeroDuration, errors.Trace(ErrInvalidTimeFormat)
+				}
 			} else {
 				// Maybe only contains date.
 				_, err = ParseDate(str)
@@ -915,6 +1078,25 @@ func ParseDuration(str string, fsp int) (Duration, error) {
 	return Duration{Duration: d, Fsp: fsp}, errors.Trace(err)
 }
 
+// ParseTimeLiteral parses a TIME literal like '2 12:30:00', '-1 00:00:01' or '123000.5'.
+// It's ParseDuration keeping all the fractional digits, and the Fsp of the result is
+// the number of fractional digits in str, up to MaxFsp. The day part is converted into hours.
+// ParseTime is taken by the datetime parser, so it's named ParseTimeLiteral.
+func ParseTimeLiteral(str string) (Duration, error) {
+	d, err := ParseDuration(str, MaxFsp)
+	if err != nil {
+		return d, errors.Trace(err)
+	}
+	d.Fsp = DefaultFsp
+	if n := strings.IndexByte(str, '.'); n >= 0 {
+		d.Fsp = len(str) - n - 1
+		if d.Fsp > MaxFsp {
+			d.Fsp = MaxFsp
+		}
+	}
+	return d, nil
+}
+
 func splitDuration(t gotime.Duration) (int, int, int, int, int) {
 	sign := 1
 	if t < 0 {
@@ -950,7 +1132,7 @@ func getTime(num int64, tp byte) (Time, error) {
 	second := int(s2 % 100)
 
 	t := Time{
-		Time: FromDate(year, month, day, hour, minute, second, 0),
+		Time: newMysqlTime(year, month, day, hour, minute, second, 0),
 		Type: tp,
 		Fsp:  DefaultFsp,
 	}
@@ -1067,10 +1249,115 @@ func ParseTime(str string, tp byte, fsp int) (Time, error) {
 }
 
 // ParseDatetime is a helper function wrapping ParseTime with datetime type and default fsp.
+// It also accepts the ODBC escapes `{ts '...'}`, `{d '...'}` and `{t '...'}` sent by
+// JDBC/ODBC clients, a `{t '...'}` time is placed on the current date.
 func ParseDatetime(str string) (Time, error) {
+	if keyword, value, ok := parseODBCEscape(str); ok {
+		if keyword == "t" {
+			d, err := ParseDuration(value, DefaultFsp)
+			if err != nil {
+				return Time{Time: ZeroTime, Type: mysql.TypeDatetime}, errors.Trace(err)
+			}
+			t, err := d.ConvertToTime(mysql.TypeDatetime)
+			return t, errors.Trace(err)
+		}
+		str = value
+	}
 	return ParseTime(str, mysql.TypeDatetime, DefaultFsp)
 }
 
+// parseODBCEscape strips the ODBC escape wrapper like `{ts '2016-12-31 23:59:59'}`.
+// It returns the lower cased keyword and the quoted literal, ok is false if str is not
+// an ODBC escape of ts, d or t.
+func parseODBCEscape(str string) (keyword string, value string, ok bool) {
+	str = strings.TrimSpace(str)
+	if len(str) < 2 || str[0] != '{' || str[len(str)-1] != '}' {
+		return "", "", false
+	}
+	str = strings.TrimSpace(str[1 : len(str)-1])
+	idx := strings.IndexAny(str, " '")
+	if idx < 0 {
+		return "", "", false
+	}
+	keyword = strings.ToLower(str[:idx])
+	if keyword != "ts" && keyword != "d" && keyword != "t" {
+		return "", "", false
+	}
+	value = strings.TrimSpace(str[idx:])
+	if len(value) < 2 || value[0] != '\'' || value[len(value)-1] != '\'' {
+		return "", "", false
+	}
+	value = value[1 : len(value)-1]
+	if strings.Contains(value, "'") {
+		return "", "", false
+	}
+	return keyword, value, true
+}
+
+// ParseDatetimeLiteral parses a datetime literal the way MySQL does and keeps the
+// fractional seconds up to MaxFsp. It accepts `-`, `/`, `.` and `:` as delimiters,
+// `T` or space between the date and time parts, and the all-numeric compact forms.
+// Malformed strings or out-of-range fields return ErrInvalidTimeFormat.
+func ParseDatetimeLiteral(str string) (mysqlTime, error) {
+	t, _, err := ParseDatetimeLiteralWithMode(str, true)
+	return t, errors.Trace(err)
+}
+
+// ParseDatetimeLiteralWithMode parses str like ParseDatetimeLiteral, but lets the
+// caller choose how out-of-range fields such as 2016-02-30 are handled.
+// In strict mode, like MySQL with STRICT_TRANS_TABLES, they are rejected;
+// otherwise the zero datetime is returned along with a warning.
+// Malformed strings are rejected in both modes.
+// A leap second like 23:59:60 is rejected in strict mode, otherwise it's clamped
+// to 23:59:59 with an ErrTruncated warning.
+func ParseDatetimeLiteralWithMode(str string, strict bool) (mysqlTime, []error, error) {
+	t, err := parseDatetime(str, MaxFsp)
+	if err != nil {
+		return ZeroTime, nil, errors.Trace(ErrInvalidTimeFormat)
+	}
+
+	var warnings []error
+	mt := t.Time.(mysqlTime).withFsp(len(datetimeFracStr(str)))
+	if mt.second == 60 && !strict {
+		mt = mt.withSecond(59)
+		warnings = append(warnings, errors.Trace(ErrTruncated))
+	}
+	if err = checkDatetimeType(mt); err != nil {
+		if strict {
+			return ZeroTime, nil, errors.Trace(ErrInvalidTimeFormat)
+		}
+		return ZeroTime, []error{errors.Trace(ErrInvalidTimeFormat)}, nil
+	}
+	return mt, warnings, nil
+}
+
+// ParseDatetimeLiteralFsp parses str like ParseDatetimeLiteral and also returns the
+// fsp detected from its fractional part, so callers can set the column precision.
+// A fractional part longer than MaxFsp is rounded and reported as a warning.
+func ParseDatetimeLiteralFsp(str string) (mysqlTime, int, []error, error) {
+	t, err := ParseDatetimeLiteral(str)
+	if err != nil {
+		return ZeroTime, DefaultFsp, nil, errors.Trace(err)
+	}
+
+	var warnings []error
+	if len(datetimeFracStr(str)) > MaxFsp {
+		warnings = append(warnings, errors.Trace(ErrTruncated))
+	}
+	return t, int(t.fsp), warnings, nil
+}
+
+// datetimeFracStr returns the fractional seconds part of a datetime literal
+// accepted by parseDatetime, or an empty string if there is none.
+func datetimeFracStr(str string) string {
+	seps := parseDateFormat(str)
+	switch len(seps) {
+	case 2, 7:
+		return seps[len(seps)-1]
+	}
+	return ""
+}
+
 // ParseTimestamp is a helper function wrapping ParseTime with timestamp type and default fsp.
 func ParseTimestamp(str string) (Time, error) {
 	return ParseTime(str, mysql.TypeTimestamp, DefaultFsp)
@@ -1206,72 +1493,65 @@ func checkDatetimeType(t TimeInternal) error {
 
 // ExtractTimeNum extracts time value number from time unit and format.
 func ExtractTimeNum(unit string, t Time) (int64, error) {
+	return Extract(unit, t.Time)
+}
+
+// Extract extracts the part of unit from t, it implements MySQL EXTRACT.
+// Compound units concatenate the parts, e.g. DAY_SECOND returns DDHHMMSS.
+// The result is negative for negative TIME value.
+func Extract(unit string, t TimeInternal) (int64, error) {
+	var neg int64 = 1
+	if t.IsNegative() {
+		neg = -1
+	}
+
+	y, mo, d := int64(t.Year()), int64(t.Month()), int64(t.Day())
+	h, m, s, f := int64(t.Hour()), int64(t.Minute()), int64(t.Second()), int64(t.Microsecond())
 	switch strings.ToUpper(unit) {
 	case "MICROSECOND":
-		return int64(t.Time.Microsecond()), nil
+		return neg * f, nil
 	case "SECOND":
-		return int64(t.Time.Second()), nil
+		return neg * s, nil
 	case "MINUTE":
-		return int64(t.Time.Minute()), nil
+		return neg * m, nil
 	case "HOUR":
-		return int64(t.Time.Hour()), nil
+		return neg * h, nil
 	case "DAY":
-		return int64(t.Time.Day()), nil
+		return d, nil
 	case "WEEK":
-		week := t.Time.Week(0)
-		return int64(week), nil
+		return int64(t.Week(0)), nil
 	case "MONTH":
-		// TODO: Consider time_zone variable.
-		t1, err := t.Time.GoTime(gotime.Local)
-		if err != nil {
-			return 0, errors.Trace(err)
-		}
-		return int64(t1.Month()), nil
+		return mo, nil
 	case "QUARTER":
-		m := int64(t.Time.Month())
 		// 1 - 3 -> 1
 		// 4 - 6 -> 2
 		// 7 - 9 -> 3
 		// 10 - 12 -> 4
-		return (m + 2) / 3, nil
+		return (mo + 2) / 3, nil
 	case "YEAR":
-		return int64(t.Time.Year()), nil
+		return y, nil
 	case "SECOND_MICROSECOND":
-		return int64(t.Time.Second())*1000000 + int64(t.Time.Microsecond()), nil
+		return neg * (s*1000000 + f), nil
 	case "MINUTE_MICROSECOND":
-		_, m, s := t.Clock()
-		return int64(m)*100000000 + int64(s)*1000000 + int64(t.Time.Microsecond()), nil
+		return neg * ((m*100+s)*1000000 + f), nil
 	case "MINUTE_SECOND":
-		_, m, s := t.Clock()
-		return int64(m*100 + s), nil
+		return neg * (m*100 + s), nil
 	case "HOUR_MICROSECOND":
-		h, m, s := t.Clock()
-		return int64(h)*10000000000 + int64(m)*100000000 + int64(s)*1000000 + int64(t.Time.Microsecond()), nil
+		return neg * ((h*10000+m*100+s)*1000000 + f), nil
 	case "HOUR_SECOND":
-		h, m, s := t.Clock()
-		return int64(h)*10000 + int64(m)*100 + int64(s), nil
+		return neg * (h*10000 + m*100 + s), nil
 	case "HOUR_MINUTE":
-		h, m, _ := t.Clock()
-		return int64(h)*100 + int64(m), nil
+		return neg * (h*100 + m), nil
 	case "DAY_MICROSECOND":
-		h, m, s := t.Clock()
-		d := t.Time.Day()
-		return int64(d*1000000+h*10000+m*100+s)*1000000 + int64(t.Time.Microsecond()), nil
+		return neg * ((d*1000000+h*10000+m*100+s)*1000000 + f), nil
 	case "DAY_SECOND":
-		h, m, s := t.Clock()
-		d := t.Time.Day()
-		return int64(d)*1000000 + int64(h)*10000 + int64(m)*100 + int64(s), nil
+		return neg * (d*1000000 + h*10000 + m*100 + s), nil
 	case "DAY_MINUTE":
-		h, m, _ := t.Clock()
-		d := t.Time.Day()
-		return int64(d)*10000 + int64(h)*100 + int64(m), nil
+		return neg * (d*10000 + h*100 + m), nil
 	case "DAY_HOUR":
-		h, _, _ := t.Clock()
-		d := t.Time.Day()
-		return int64(d)*100 + int64(h), nil
+		return neg * (d*100 + h), nil
 	case "YEAR_MONTH":
-		y, m := t.Time.Year(), t.Time.Month()
-		return int64(y)*100 + int64(m), nil
+		return y*100 + mo, nil
 	default:
 		return 0, errors.Errorf("invalid unit %s", unit)
 	}
@@ -1570,6 +1850,137 @@ func ExtractTimeValue(unit string, format string) (int64, int64, int64, gotime.D
 	}
 }
 
+// Interval holds the parts of an INTERVAL expression like INTERVAL '2-3' YEAR_MONTH.
+type Interval struct {
+	Year        int64
+	Month       int64
+	Day         int64
+	Hour        int64
+	Minute      int64
+	Second      int64
+	Microsecond int64
+}
+
+// intervalLayouts maps the interval units to the layouts of their values. In a layout,
+// Y is years, Q quarters, M months, W weeks, D days, H hours, I minutes, S seconds,
+// U microseconds and F the fraction of a second, the other characters are delimiters.
+var intervalLayouts = map[string]string{
+	"MICROSECOND":        "U",
+	"SECOND":             "S",
+	"MINUTE":             "I",
+	"HOUR":               "H",
+	"DAY":                "D",
+	"WEEK":               "W",
+	"MONTH":              "M",
+	"QUARTER":            "Q",
+	"YEAR":               "Y",
+	"SECOND_MICROSECOND": "S.F",
+	"MINUTE_MICROSECOND": "I:S.F",
+	"MINUTE_SECOND":      "I:S",
+	"HOUR_MICROSECOND":   "H:I:S.F",
+	"HOUR_SECOND":        "H:I:S",
+	"HOUR_MINUTE":        "H:I",
+	"DAY_MICROSECOND":    "D H:I:S.F",
+	"DAY_SECOND":         "D H:I:S",
+	"DAY_MINUTE":         "D H:I",
+	"DAY_HOUR":           "D H",
+	"YEAR_MONTH":         "Y-M",
+}
+
+// ParseInterval decomposes the interval value of unit into year, month, day, hour, minute,
+// second and microsecond deltas, e.g. '1:30' MINUTE_SECOND is 1 minute and 30 seconds.
+// A leading '-' negates all the parts, so '-1:30' MINUTE_SECOND is -1 minute and -30 seconds,
+// the parts after it must be unsigned numbers. Microseconds, seconds and minutes beyond their
+// ranges are carried into the larger parts, ErrDatetimeOutOfRange is returned on overflow.
+func ParseInterval(value, unit string) (Interval, error) {
+	layout, ok := intervalLayouts[strings.ToUpper(unit)]
+	if !ok {
+		return Interval{}, errors.Errorf("invalid time unit - %s", unit)
+	}
+	value = strings.TrimSpace(value)
+	neg := strings.HasPrefix(value, "-")
+	if neg {
+		value = value[1:]
+	}
+
+	var iv Interval
+	rest := value
+	for i := 0; i < len(layout); i++ {
+		part := layout[i]
+		switch part {
+		case '-', ' ', ':', '.':
+			if len(rest) == 0 || rest[0] != part {
+				return Interval{}, errors.Errorf("invalid time format - %s", value)
+			}
+			rest = rest[1:]
+			continue
+		}
+
+		n := 0
+		for n < len(rest) && rest[n] >= '0' && rest[n] <= '9' {
+			n++
+		}
+		if n == 0 {
+			return Interval{}, errors.Errorf("invalid time format - %s", value)
+		}
+		digits := rest[:n]
+		rest = rest[n:]
+		if part == 'F' {
+			digits = alignFrac(digits, MaxFsp)
+		}
+		v, err := strconv.ParseInt(digits, 10, 64)
+		if err != nil {
+			return Interval{}, errors.Trace(ErrDatetimeOutOfRange)
+		}
+		switch part {
+		case 'Y':
+			iv.Year = v
+		case 'Q':
+			iv.Month, err = mulInterval(v, 3, math.MaxInt64)
+		case 'M':
+			iv.Month = v
+		case 'W':
+			iv.Day, err = mulInterval(v, 7, math.MaxInt64)
+		case 'D':
+			iv.Day = v
+		case 'H':
+			iv.Hour = v
+		case 'I':
+			iv.Minute = v
+		case 'S':
+			iv.Second = v
+		case 'U', 'F':
+			iv.Microsecond = v
+		}
+		if err != nil {
+			return Interval{}, errors.Trace(err)
+		}
+	}
+	if len(rest) != 0 {
+		return Interval{}, errors.Errorf("invalid time format - %s", value)
+	}
+
+	for _, c := range []struct {
+		upper, lower *int64
+		base         int64
+	}{
+		{&iv.Second, &iv.Microsecond, 1e6},
+		{&iv.Minute, &iv.Second, 60},
+		{&iv.Hour, &iv.Minute, 60},
+	} {
+		carry := *c.lower / c.base
+		if *c.upper > math.MaxInt64-carry {
+			return Interval{}, errors.Trace(ErrDatetimeOutOfRange)
+		}
+		*c.upper += carry
+		*c.lower %= c.base
+	}
+	if neg {
+		iv = Interval{-iv.Year, -iv.Month, -iv.Day, -iv.Hour, -iv.Minute, -iv.Second, -iv.Microsecond}
+	}
+	return iv, nil
+}
+
 // IsClockUnit returns true when unit is interval unit with hour, minute or second.
 func IsClockUnit(unit string) bool {
 	switch strings.ToUpper(unit) {
@@ -1608,11 +2019,53 @@ func ParseTimeFromInt64(num int64) (Time, error) {
 // according to layout.
 // See http://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_date-format
 func (t Time) DateFormat(layout string) (string, error) {
+	return formatTime(t.Time, layout)
+}
+
+// DateFormatWithNames is like DateFormat, but the month and weekday names are taken from names.
+func (t Time) DateFormatWithNames(layout string, names *TimeNames) (string, error) {
+	return formatTimeWithNames(t.Time, layout, names)
+}
+
+// TimeNames holds the month and weekday names used by DATE_FORMAT, like MySQL lc_time_names.
+// The weekday names start from Monday.
+type TimeNames struct {
+	MonthNames         []string
+	AbbrevMonthNames   []string
+	WeekdayNames       []string
+	AbbrevWeekdayNames []string
+}
+
+// EnUSTimeNames is the en_US TimeNames, which is the default of MySQL.
+var EnUSTimeNames = &TimeNames{
+	MonthNames: MonthNames,
+	AbbrevMonthNames: []string{
+		"Jan", "Feb", "Mar", "Apr", "May", "Jun",
+		"Jul", "Aug", "Sep", "Oct", "Nov", "Dec",
+	},
+	WeekdayNames:       WeekdayNames,
+	AbbrevWeekdayNames: []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"},
+}
+
+// formatTime formats t according to the MySQL DATE_FORMAT layout with the en_US names.
+// Unknown specifiers are written as the literal character after '%'.
+func formatTime(t TimeInternal, layout string) (string, error) {
+	return formatTimeWithNames(t, layout, EnUSTimeNames)
+}
+
+func formatTimeWithNames(t TimeInternal, layout string, names *TimeNames) (string, error) {
 	var buf bytes.Buffer
+	// Only a TIME value may be negative, its sign goes before the time part like MySQL
+	// -12:30:00, rather than before the zero date part.
+	needSign := t.IsNegative()
 	inPatternMatch := false
 	for _, b := range layout {
 		if inPatternMatch {
-			if err := t.convertDateFormat(b, &buf); err != nil {
+			if needSign && strings.ContainsRune(timePartSpecifiers, b) {
+				buf.WriteByte('-')
+				needSign = false
+			}
+			if err := convertDateFormat(t, b, names, &buf); err != nil {
 				return "", errors.Trace(err)
 			}
 			inPatternMatch = false
@@ -1629,118 +2082,166 @@ func (t Time) DateFormat(layout string) (string, error) {
 	return buf.String(), nil
 }
 
-var abbrevWeekdayName = []string{
-	"Sun", "Mon", "Tue",
-	"Wed", "Thu", "Fri", "Sat",
+// timePartSpecifiers are the DATE_FORMAT specifiers of the time part, the sign of a negative
+// TIME value is written before the first of them.
+const timePartSpecifiers = "HkhIlrTiSsf"
+
+// standardFormats maps the kind and standard of GET_FORMAT to the format string.
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_get-format
+var standardFormats = map[string]map[string]string{
+	"DATE": {
+		"USA":      "%m.%d.%Y",
+		"JIS":      "%Y-%m-%d",
+		"ISO":      "%Y-%m-%d",
+		"EUR":      "%d.%m.%Y",
+		"INTERNAL": "%Y%m%d",
+	},
+	"DATETIME": {
+		"USA":      "%Y-%m-%d %H.%i.%s",
+		"JIS":      "%Y-%m-%d %H:%i:%s",
+		"ISO":      "%Y-%m-%d %H:%i:%s",
+		"EUR":      "%Y-%m-%d %H.%i.%s",
+		"INTERNAL": "%Y%m%d%H%i%s",
+	},
+	"TIME": {
+		"USA":      "%h:%i:%s %p",
+		"JIS":      "%H:%i:%s",
+		"ISO":      "%H:%i:%s",
+		"EUR":      "%H.%i.%s",
+		"INTERNAL": "%H%i%s",
+	},
+}
+
+// GetFormat returns the format string for DATE_FORMAT and STR_TO_DATE, it implements MySQL GET_FORMAT.
+// kind can be DATE, TIME, DATETIME or TIMESTAMP, and standard can be USA, JIS, ISO, EUR or INTERNAL.
+func GetFormat(kind, standard string) (string, error) {
+	kind = strings.ToUpper(kind)
+	if kind == "TIMESTAMP" {
+		kind = "DATETIME"
+	}
+	if formats, ok := standardFormats[kind]; ok {
+		if format, ok := formats[strings.ToUpper(standard)]; ok {
+			return format, nil
+		}
+	}
+	return "", errors.Errorf("invalid format kind %s or standard %s", kind, standard)
 }
 
-func (t Time) convertDateFormat(b rune, buf *bytes.Buffer) error {
+func convertDateFormat(t TimeInternal, b rune, names *TimeNames, buf *bytes.Buffer) error {
 	switch b {
 	case 'b':
-		m := t.Time.Month()
+		m := t.Month()
 		if m == 0 || m > 12 {
 			return errors.Trace(ErrInvalidTimeFormat)
 		}
-		buf.WriteString(MonthNames[m-1][:3])
+		buf.WriteString(names.AbbrevMonthNames[m-1])
 	case 'M':
-		m := t.Time.Month()
+		m := t.Month()
 		if m == 0 || m > 12 {
 			return errors.Trace(ErrInvalidTimeFormat)
 		}
-		buf.WriteString(MonthNames[m-1])
+		buf.WriteString(names.MonthNames[m-1])
 	case 'm':
-		fmt.Fprintf(buf, "%02d", t.Time.Month())
+		fmt.Fprintf(buf, "%02d", t.Month())
 	case 'c':
-		fmt.Fprintf(buf, "%d", t.Time.Month())
+		fmt.Fprintf(buf, "%d", t.Month())
 	case 'D':
-		fmt.Fprintf(buf, "%d%s", t.Time.Day(), abbrDayOfMonth(t.Time.Day()))
+		fmt.Fprintf(buf, "%d%s", t.Day(), abbrDayOfMonth(t.Day()))
 	case 'd':
-		fmt.Fprintf(buf, "%02d", t.Time.Day())
+		fmt.Fprintf(buf, "%02d", t.Day())
 	case 'e':
-		fmt.Fprintf(buf, "%d", t.Time.Day())
+		fmt.Fprintf(buf, "%d", t.Day())
 	case 'j':
-		fmt.Fprintf(buf, "%03d", t.Time.YearDay())
+		fmt.Fprintf(buf, "%03d", t.YearDay())
 	case 'H':
-		fmt.Fprintf(buf, "%02d", t.Time.Hour())
+		fmt.Fprintf(buf, "%02d", t.Hour())
 	case 'k':
-		fmt.Fprintf(buf, "%d", t.Time.Hour())
+		fmt.Fprintf(buf, "%d", t.Hour())
 	case 'h', 'I':
-		t := t.Time.Hour()
-		if t == 0 || t == 12 {
+		h := t.Hour()
+		if h == 0 || h == 12 {
 			fmt.Fprintf(buf, "%02d", 12)
 		} else {
-			fmt.Fprintf(buf, "%02d", t%12)
+			fmt.Fprintf(buf, "%02d", h%12)
 		}
 	case 'l':
-		t := t.Time.Hour()
-		if t == 0 || t == 12 {
+		h := t.Hour()
+		if h == 0 || h == 12 {
 			fmt.Fprintf(buf, "%d", 12)
 		} else {
-			fmt.Fprintf(buf, "%d", t%12)
+			fmt.Fprintf(buf, "%d", h%12)
 		}
 	case 'i':
-		fmt.Fprintf(buf, "%02d", t.Time.Minute())
+		fmt.Fprintf(buf, "%02d", t.Minute())
 	case 'p':
-		if t.Time.Hour() < 12 {
+		if t.Hour() < 12 {
 			buf.WriteString("AM")
 		} else {
 			buf.WriteString("PM")
 		}
 	case 'r':
-		h := t.Time.Hour()
+		h := t.Hour()
 		switch {
 		case h == 0:
-			fmt.Fprintf(buf, "%02d:%02d:%02d AM", 12, t.Time.Minute(), t.Time.Second())
+			fmt.Fprintf(buf, "%02d:%02d:%02d AM", 12, t.Minute(), t.Second())
 		case h == 12:
-			fmt.Fprintf(buf, "%02d:%02d:%02d PM", 12, t.Time.Minute(), t.Time.Second())
+			fmt.Fprintf(buf, "%02d:%02d:%02d PM", 12, t.Minute(), t.Second())
 		case h < 12:
-			fmt.Fprintf(buf, "%02d:%02d:%02d AM", h, t.Time.Minute(), t.Time.Second())
+			fmt.Fprintf(buf, "%02d:%02d:%02d AM", h, t.Minute(), t.Second())
 		default:
-			fmt.Fprintf(buf, "%02d:%02d:%02d PM", h-12, t.Time.Minute(), t.Time.Second())
+			fmt.Fprintf(buf, "%02d:%02d:%02d PM", h-12, t.Minute(), t.Second())
 		}
 	case 'T':
-		fmt.Fprintf(buf, "%02d:%02d:%02d", t.Time.Hour(), t.Time.Minute(), t.Time.Second())
+		fmt.Fprintf(buf, "%02d:%02d:%02d", t.Hour(), t.Minute(), t.Second())
 	case 'S', 's':
-		fmt.Fprintf(buf, "%02d", t.Time.Second())
+		fmt.Fprintf(buf, "%02d", t.Second())
 	case 'f':
-		fmt.Fprintf(buf, "%06d", t.Time.Microsecond())
+		fmt.Fprintf(buf, "%06d", t.Microsecond())
 	case 'U':
-		w := t.Time.Week(0)
+		w := t.Week(0)
 		fmt.Fprintf(buf, "%02d", w)
 	case 'u':
-		w := t.Time.Week(1)
+		w := t.Week(1)
 		fmt.Fprintf(buf, "%02d", w)
 	case 'V':
-		w := t.Time.Week(2)
+		w := t.Week(2)
 		fmt.Fprintf(buf, "%02d", w)
 	case 'v':
-		_, w := t.Time.YearWeek(3)
+		_, w := t.YearWeek(3)
 		fmt.Fprintf(buf, "%02d", w)
 	case 'a':
-		weekday := t.Time.Weekday()
-		buf.WriteString(abbrevWeekdayName[weekday])
+		// Weekday names are meaningless for dates containing zero month or day.
+		if t.Month() == 0 || t.Day() == 0 {
+			break
+		}
+		weekday := t.Weekday()
+		buf.WriteString(names.AbbrevWeekdayNames[(weekday+6)%7])
 	case 'W':
-		buf.WriteString(t.Time.Weekday().String())
+		if t.Month() == 0 || t.Day() == 0 {
+			break
+		}
+		weekday := t.Weekday()
+		buf.WriteString(names.WeekdayNames[(weekday+6)%7])
 	case 'w':
-		fmt.Fprintf(buf, "%d", t.Time.Weekday())
+		fmt.Fprintf(buf, "%d", t.Weekday())
 	case 'X':
-		year, _ := t.Time.YearWeek(2)
+		year, _ := t.YearWeek(2)
 		if year < 0 {
 			fmt.Fprintf(buf, "%v", math.MaxUint32)
 		} else {
 			fmt.Fprintf(buf, "%04d", year)
 		}
 	case 'x':
-		year, _ := t.Time.YearWeek(3)
+		year, _ := t.YearWeek(3)
 		if year < 0 {
 			fmt.Fprintf(buf, "%v", math.MaxUint32)
 		} else {
 			fmt.Fprintf(buf, "%04d", year)
 		}
 	case 'Y':
-		fmt.Fprintf(buf, "%04d", t.Time.Year())
+		fmt.Fprintf(buf, "%04d", t.Year())
 	case 'y':
-		str := fmt.Sprintf("%04d", t.Time.Year())
+		str := fmt.Sprintf("%04d", t.Year())
 		buf.WriteString(str[2:])
 	default:
 		buf.WriteRune(b)
@@ -1962,7 +2463,7 @@ func hour24TwoDigits(t *mysqlTime, input string, ctx map[string]int) (string, bo
 	if !succ || v >= 24 {
 		return input, false
 	}
-	t.hour = uint8(v)
+	t.hour = uint32(v)
 	return input[2:], true
 }
 
@@ -2009,9 +2510,9 @@ func time12Hour(t *mysqlTime, input string, ctx map[string]int) (string, bool) {
 	remain := skipWhiteSpace(input[8:])
 	switch {
 	case strings.HasPrefix(remain, "AM"):
-		t.hour = uint8(hour)
+		t.hour = uint32(hour)
 	case strings.HasPrefix(remain, "PM"):
-		t.hour = uint8(hour + 12)
+		t.hour = uint32(hour + 12)
 	default:
 		return input, false
 	}
@@ -2044,7 +2545,7 @@ func time24Hour(t *mysqlTime, input string, ctx map[string]int) (string, bool) {
 		return input, false
 	}
 
-	t.hour = uint8(hour)
+	t.hour = uint32(hour)
 	t.minute = uint8(minute)
 	t.second = uint8(second)
 	return input[8:], true
@@ -2111,7 +2612,7 @@ func hour24Numeric(t *mysqlTime, input string, ctx map[string]int) (string, bool
 	if len(remain) == len(input) || v > 23 {
 		return input, false
 	}
-	t.hour = uint8(v)
+	t.hour = uint32(v)
 	return remain, true
 }
 
@@ -2121,7 +2622,7 @@ func hour12Numeric(t *mysqlTime, input string, ctx map[string]int) (string, bool
 	if len(remain) == len(input) || v > 12 || v == 0 {
 		return input, false
 	}
-	t.hour = uint8(v)
+	t.hour = uint32(v)
 	return remain, true
 }
 
@@ -14,10 +14,12 @@
 package types
 
 import (
+	"math"
 	"time"
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/tidb/mysql"
+	"github.com/pingcap/tidb/terror"
 	"github.com/pingcap/tidb/util/testleak"
 )
 
@@ -95,6 +97,48 @@ func (s *testTimeSuite) TestDateTime(c *C) {
 	}
 }
 
+func (s *testTimeSuite) TestParseDatetimeODBCEscape(c *C) {
+	defer testleak.AfterTest(c)()
+	defer func(f func() time.Time) { nowFunc = f }(nowFunc)
+	nowFunc = func() time.Time { return time.Date(2016, 12, 31, 12, 0, 0, 0, time.Local) }
+
+	table := []struct {
+		Input  string
+		Expect string
+	}{
+		{"{ts '2016-12-31 23:59:59'}", "2016-12-31 23:59:59"},
+		{"{TS '2016-12-31 23:59:59'}", "2016-12-31 23:59:59"},
+		{" { ts  '2016-12-31 23:59:59' } ", "2016-12-31 23:59:59"},
+		{"{ts'20161231235959'}", "2016-12-31 23:59:59"},
+		{"{d '2016-12-31'}", "2016-12-31 00:00:00"},
+		{"{D '16-12-31'}", "2016-12-31 00:00:00"},
+		{"{t '23:59:59'}", "2016-12-31 23:59:59"},
+		{"{t '10:30'}", "2016-12-31 10:30:00"},
+	}
+
+	for i, test := range table {
+		t, err := ParseDatetime(test.Input)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(t.String(), Equals, test.Expect, Commentf("%d failed.", i))
+	}
+
+	errTable := []string{
+		"{ts '2016-13-31 23:59:59'}",
+		"{d '2016-02-30'}",
+		"{t '12:30:45:10'}",
+		"{x '2016-12-31'}",
+		"{ts 2016-12-31 23:59:59}",
+		"{ts '2016-12-31 23:59:59'",
+		"{ts '2016-12-31' '23:59:59'}",
+		"{}",
+	}
+
+	for i, test := range errTable {
+		_, err := ParseDatetime(test)
+		c.Assert(err, NotNil, Commentf("%d failed.", i))
+	}
+}
+
 func (s *testTimeSuite) TestTimestamp(c *C) {
 	defer testleak.AfterTest(c)()
 	table := []struct {
@@ -304,6 +348,39 @@ func (s *testTimeSuite) TestYear(c *C) {
 
 }
 
+func (s *testTimeSuite) TestAdjustYear(c *C) {
+	defer testleak.AfterTest(c)()
+	table := []struct {
+		Input  int
+		Expect int
+	}{
+		{0, 2000},
+		{1, 2001},
+		{69, 2069},
+		{70, 1970},
+		{99, 1999},
+		{100, 100},
+		{1969, 1969},
+		{2069, 2069},
+		{9999, 9999},
+	}
+
+	for _, test := range table {
+		c.Assert(adjustYear(test.Input), Equals, test.Expect)
+	}
+
+	// Two digit year is adjusted by the numeric literal parsers and period functions.
+	t, err := ParseDateUint64(691231)
+	c.Assert(err, IsNil)
+	c.Assert(t.Year(), Equals, 2069)
+	t, err = ParseDateUint64(700101)
+	c.Assert(err, IsNil)
+	c.Assert(t.Year(), Equals, 1970)
+	c.Assert(PeriodAdd(6912, 1), Equals, uint64(207001))
+	c.Assert(PeriodAdd(7001, -1), Equals, uint64(196912))
+	c.Assert(PeriodDiff(6912, 7001), Equals, int64(1199))
+}
+
 func (s *testTimeSuite) getLocation(c *C) *time.Location {
 	locations := []string{"Asia/Shanghai", "Europe/Berlin"}
 	timeFormat := "Jan 2, 2006 at 3:04pm (MST)"
@@ -730,11 +807,15 @@ func (s *testTimeSuite) TestDurationClock(c *C) {
 		{"11:11:11.11", 11, 11, 11, 110000},
 		{"1 11:11:11.000011", 35, 11, 11, 11},
 		{"2010-10-10 11:11:11.000011", 11, 11, 11, 11},
+		{"-12:30:00", 12, 30, 0, 0},
+		{"-838:59:59", 838, 59, 59, 0},
+		{"-01:02:03.000004", 1, 2, 3, 4},
 	}
 
 	for _, t := range tbl {
 		d, err := ParseDuration(t.Input, MaxFsp)
 		c.Assert(err, IsNil)
+		c.Assert(d.Negative(), Equals, t.Input[0] == '-')
 		c.Assert(d.Hour(), Equals, t.Hour)
 		c.Assert(d.Minute(), Equals, t.Minute)
 		c.Assert(d.Second(), Equals, t.Second)
@@ -742,6 +823,110 @@ func (s *testTimeSuite) TestDurationClock(c *C) {
 	}
 }
 
+func (s *testTimeSuite) TestDurationConvert(c *C) {
+	defer testleak.AfterTest(c)()
+	tbl := []struct {
+		Input   string
+		Seconds int64
+		Time    mysqlTime
+	}{
+		{"11:11:11.11", 40271, newMysqlTime(0, 0, 0, 11, 11, 11, 110000)},
+		{"-11:11:11.11", -40271, SecToTime(-40271, -110000)},
+		{"-00:00:00.5", 0, SecToTime(0, -500000)},
+		{"838:59:59", 3020399, newMysqlTime(0, 0, 0, 838, 59, 59, 0)},
+		{"-838:59:59", -3020399, SecToTime(-3020399, 0)},
+		{"00:00:00", 0, ZeroTime},
+	}
+
+	for i, t := range tbl {
+		d, err := ParseDuration(t.Input, MaxFsp)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(d.ToSeconds(), Equals, t.Seconds, Commentf("%d failed.", i))
+		c.Assert(d.toMysqlTime(), Equals, t.Time, Commentf("%d failed.", i))
+		c.Assert(DurationFromTime(t.Time, MaxFsp), Equals, d, Commentf("%d failed.", i))
+		c.Assert(DurationFromTime(t.Time, MaxFsp).String(), Equals, d.String(), Commentf("%d failed.", i))
+	}
+
+	// The date part is ignored, and the hour is capped at 838.
+	d := DurationFromTime(newMysqlTime(2016, 12, 31, 23, 59, 59, 0), DefaultFsp)
+	c.Assert(d.String(), Equals, "23:59:59")
+	d = DurationFromTime(newMysqlTime(0, 0, 0, 900, 0, 0, 0), DefaultFsp)
+	c.Assert(d.Duration, Equals, MaxTime)
+	c.Assert(d.String(), Equals, "838:59:59")
+	d = DurationFromTime(SecToTime(-3020399, -1), MaxFsp)
+	c.Assert(d.Duration, Equals, MinTime)
+
+	// The result of calcTimeDiff can be converted to a Duration.
+	seconds, microseconds, neg := calcTimeDiff(newMysqlTime(2016, 1, 1, 0, 0, 0, 0), newMysqlTime(2016, 1, 1, 12, 30, 0, 500000), 1)
+	d = durationFromSec(seconds, microseconds, neg, 1)
+	c.Assert(d.String(), Equals, "-12:30:00.5")
+	seconds, microseconds, neg = calcTimeDiff(newMysqlTime(2016, 3, 1, 0, 0, 0, 0), newMysqlTime(2016, 1, 1, 0, 0, 0, 0), 1)
+	d = durationFromSec(seconds, microseconds, neg, 0)
+	c.Assert(d.String(), Equals, "838:59:59")
+}
+
+func (s *testTimeSuite) TestDurationAddSub(c *C) {
+	defer testleak.AfterTest(c)()
+	tbl := []struct {
+		Lhs      string
+		Rhs      string
+		Add      string
+		Sub      string
+		Overflow bool
+	}{
+		{"10:00:00", "01:30:00", "11:30:00", "08:30:00", false},
+		{"01:00:00", "10:00:00", "11:00:00", "-09:00:00", false},
+		{"-01:00:00", "10:00:00", "09:00:00", "-11:00:00", false},
+		{"-01:00:00", "-10:00:00", "-11:00:00", "09:00:00", false},
+		{"00:00:00.5", "00:00:01", "00:00:01.500000", "-00:00:00.500000", false},
+		{"838:59:59", "-838:59:59", "00:00:00", "838:59:59", true},
+		{"800:00:00", "38:59:59", "838:59:59", "761:00:01", false},
+		{"-800:00:00", "38:59:59", "-761:00:01", "-838:59:59", false},
+	}
+
+	for i, t := range tbl {
+		lhs, err := ParseDuration(t.Lhs, MaxFsp)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		rhs, err := ParseDuration(t.Rhs, DefaultFsp)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+
+		d, err := lhs.Add(rhs)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(d.Fsp, Equals, MaxFsp, Commentf("%d failed.", i))
+		cmp, err := d.CompareString(t.Add)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(cmp, Equals, 0, Commentf("%d failed.", i))
+
+		d, err = lhs.Sub(rhs)
+		if t.Overflow {
+			c.Assert(err, NotNil, Commentf("%d failed.", i))
+		} else {
+			c.Assert(err, IsNil, Commentf("%d failed.", i))
+		}
+		cmp, err = d.CompareString(t.Sub)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(cmp, Equals, 0, Commentf("%d failed.", i))
+	}
+
+	// Clipped at the boundary.
+	max, err := ParseDuration("838:59:59", DefaultFsp)
+	c.Assert(err, IsNil)
+	d, err := max.Add(Duration{Duration: time.Second})
+	c.Assert(err, NotNil)
+	c.Assert(d.Duration, Equals, MaxTime)
+	d, err = max.Add(Duration{Duration: time.Microsecond, Fsp: MaxFsp})
+	c.Assert(err, NotNil)
+	c.Assert(d.Duration, Equals, MaxTime)
+	c.Assert(d.Fsp, Equals, MaxFsp)
+	min := Duration{Duration: -max.Duration}
+	d, err = min.Sub(Duration{Duration: time.Second})
+	c.Assert(err, NotNil)
+	c.Assert(d.Duration, Equals, MinTime)
+	d, err = min.Sub(min)
+	c.Assert(err, IsNil)
+	c.Assert(d.Duration, Equals, time.Duration(0))
+}
+
 func (s *testTimeSuite) TestParseDateFormat(c *C) {
 	defer testleak.AfterTest(c)()
 	tbl := []struct {
@@ -765,3 +950,319 @@ func (s *testTimeSuite) TestParseDateFormat(c *C) {
 		c.Assert(r, DeepEquals, t.Result)
 	}
 }
+
+func (s *testTimeSuite) TestExtract(c *C) {
+	defer testleak.AfterTest(c)()
+	tm := newMysqlTime(2019, 7, 2, 1, 2, 3, 123)
+	tbl := []struct {
+		Unit   string
+		Expect int64
+	}{
+		{"MICROSECOND", 123},
+		{"SECOND", 3},
+		{"MINUTE", 2},
+		{"HOUR", 1},
+		{"DAY", 2},
+		{"WEEK", 26},
+		{"MONTH", 7},
+		{"QUARTER", 3},
+		{"YEAR", 2019},
+		{"SECOND_MICROSECOND", 3000123},
+		{"MINUTE_MICROSECOND", 203000123},
+		{"MINUTE_SECOND", 203},
+		{"HOUR_MICROSECOND", 10203000123},
+		{"HOUR_SECOND", 10203},
+		{"HOUR_MINUTE", 102},
+		{"DAY_MICROSECOND", 2010203000123},
+		{"DAY_SECOND", 2010203},
+		{"DAY_MINUTE", 20102},
+		{"DAY_HOUR", 201},
+		{"YEAR_MONTH", 201907},
+		{"year_month", 201907},
+	}
+
+	for _, t := range tbl {
+		v, err := Extract(t.Unit, tm)
+		c.Assert(err, IsNil)
+		c.Assert(v, Equals, t.Expect, Commentf("unit %s", t.Unit))
+	}
+
+	// Negative TIME value -12:30:45.5
+	neg, err := MakeTime(-12, 30, 45, 500000)
+	c.Assert(err, IsNil)
+	negTbl := []struct {
+		Unit   string
+		Expect int64
+	}{
+		{"HOUR", -12},
+		{"MINUTE", -30},
+		{"SECOND", -45},
+		{"MICROSECOND", -500000},
+		{"HOUR_MINUTE", -1230},
+		{"HOUR_SECOND", -123045},
+		{"MINUTE_SECOND", -3045},
+		{"SECOND_MICROSECOND", -45500000},
+		{"DAY_HOUR", -12},
+		{"DAY_SECOND", -123045},
+	}
+	for _, t := range negTbl {
+		v, err := Extract(t.Unit, neg)
+		c.Assert(err, IsNil)
+		c.Assert(v, Equals, t.Expect, Commentf("unit %s", t.Unit))
+	}
+
+	_, err = Extract("DAY_YEAR", tm)
+	c.Assert(err, NotNil)
+}
+
+func (s *testTimeSuite) TestNowFunc(c *C) {
+	defer testleak.AfterTest(c)()
+	defer func(f func() time.Time) { nowFunc = f }(nowFunc)
+
+	instant := time.Date(2016, 12, 31, 23, 59, 59, 123456000, time.Local)
+	nowFunc = func() time.Time { return instant }
+
+	c.Assert(Now(time.Local, MaxFsp), Equals, newMysqlTime(2016, 12, 31, 23, 59, 59, 123456).withFsp(MaxFsp))
+	c.Assert(CurDate(time.Local), Equals, newMysqlTime(2016, 12, 31, 0, 0, 0, 0))
+	c.Assert(CurTime(time.Local, 2), Equals, newMysqlTime(0, 0, 0, 23, 59, 59, 120000).withFsp(2))
+	c.Assert(CurrentTime(mysql.TypeDatetime).Time, Equals, newMysqlTime(2016, 12, 31, 23, 59, 59, 123456))
+
+	d := Duration{Duration: 90 * time.Minute, Fsp: 0}
+	t, err := d.ConvertToTime(mysql.TypeDatetime)
+	c.Assert(err, IsNil)
+	c.Assert(t.Time, Equals, newMysqlTime(2016, 12, 31, 1, 30, 0, 0))
+}
+
+func (s *testTimeSuite) TestTimeKind(c *C) {
+	defer testleak.AfterTest(c)()
+	tbl := []struct {
+		t         mysqlTime
+		tp        byte
+		guessKind TimeKind
+		kind      TimeKind
+	}{
+		{newMysqlTime(2016, 12, 31, 23, 59, 59, 0), mysql.TypeDatetime, TimeKindDatetime, TimeKindDatetime},
+		{newMysqlTime(2016, 12, 31, 0, 0, 0, 0), mysql.TypeDate, TimeKindDate, TimeKindDate},
+		// A datetime at midnight looks like a date.
+		{newMysqlTime(2016, 12, 31, 0, 0, 0, 0), mysql.TypeDatetime, TimeKindDate, TimeKindDatetime},
+		{newMysqlTime(2016, 12, 31, 0, 0, 0, 1), mysql.TypeTimestamp, TimeKindDatetime, TimeKindDatetime},
+		{newMysqlTime(0, 0, 0, 12, 0, 0, 0), mysql.TypeDuration, TimeKindTime, TimeKindTime},
+		// The all-zero value is ambiguous.
+		{ZeroTime, mysql.TypeDate, TimeKindDatetime, TimeKindDate},
+		{ZeroTime, mysql.TypeDatetime, TimeKindDatetime, TimeKindDatetime},
+		{ZeroTime, mysql.TypeDuration, TimeKindDatetime, TimeKindTime},
+	}
+
+	for i, t := range tbl {
+		c.Assert(t.t.Kind(), Equals, t.guessKind, Commentf("%d failed.", i))
+		c.Assert(Time{Time: t.t, Type: t.tp}.Kind(), Equals, t.kind, Commentf("%d failed.", i))
+	}
+
+	neg := newMysqlTime(0, 0, 0, 0, 0, 0, 0)
+	neg.neg = true
+	c.Assert(neg.Kind(), Equals, TimeKindTime)
+}
+
+func (s *testTimeSuite) TestClipTime(c *C) {
+	defer testleak.AfterTest(c)()
+	tbl := []struct {
+		d       time.Duration
+		expect  time.Duration
+		clipped bool
+	}{
+		{0, 0, false},
+		{MaxTime, MaxTime, false},
+		{MinTime, MinTime, false},
+		{MaxTime + time.Microsecond, MaxTime, true},
+		{MinTime - time.Microsecond, MinTime, true},
+		{MaxTime + time.Second, MaxTime, true},
+		{MinTime - time.Second, MinTime, true},
+		{839 * time.Hour, MaxTime, true},
+		{-839 * time.Hour, MinTime, true},
+		{MaxTime - time.Microsecond, MaxTime - time.Microsecond, false},
+	}
+
+	for i, t := range tbl {
+		d := Duration{Duration: t.d, Fsp: MaxFsp}
+		c.Assert(d.ClipTime(), Equals, t.clipped, Commentf("%d failed.", i))
+		c.Assert(d.Duration, Equals, t.expect, Commentf("%d failed.", i))
+		c.Assert(d.Fsp, Equals, MaxFsp, Commentf("%d failed.", i))
+	}
+}
+
+func (s *testTimeSuite) TestParseTimeLiteral(c *C) {
+	defer testleak.AfterTest(c)()
+	tbl := []struct {
+		input  string
+		expect string
+	}{
+		{"2 12:30:00", "60:30:00"},
+		{"-1 00:00:01", "-24:00:01"},
+		{"0 00:00:00", "00:00:00"},
+		{"34 22:59:59", "838:59:59"},
+		{"2 12", "60:00:00"},
+		{"2 12:30", "60:30:00"},
+		{"1 00:00:00.5", "24:00:00.5"},
+		{"-1 00:00:00.000001", "-24:00:00.000001"},
+		{"123000", "12:30:00"},
+		{"-123000.25", "-12:30:00.25"},
+		{"12:30:00.10", "12:30:00.10"},
+		{"12:30:00.1234567", "12:30:00.123457"},
+	}
+
+	for i, t := range tbl {
+		d, err := ParseTimeLiteral(t.input)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(d.String(), Equals, t.expect, Commentf("%d failed.", i))
+	}
+
+	errTbl := []string{
+		"35 00:00:00",
+		"-35 00:00:00",
+		"2 12:30:00:00",
+		"abc",
+	}
+	for i, t := range errTbl {
+		_, err := ParseTimeLiteral(t)
+		c.Assert(err, NotNil, Commentf("%d failed.", i))
+	}
+}
+
+func (s *testTimeSuite) TestParseInterval(c *C) {
+	defer testleak.AfterTest(c)()
+	tbl := []struct {
+		value  string
+		unit   string
+		expect Interval
+	}{
+		{"2-3", "YEAR_MONTH", Interval{Year: 2, Month: 3}},
+		{"-2-3", "YEAR_MONTH", Interval{Year: -2, Month: -3}},
+		{"1 2:3:4", "DAY_SECOND", Interval{Day: 1, Hour: 2, Minute: 3, Second: 4}},
+		{"-1 02:03:04", "day_second", Interval{Day: -1, Hour: -2, Minute: -3, Second: -4}},
+		{"1:30", "MINUTE_SECOND", Interval{Minute: 1, Second: 30}},
+		{"-1:30", "MINUTE_SECOND", Interval{Minute: -1, Second: -30}},
+		{"90:30", "MINUTE_SECOND", Interval{Hour: 1, Minute: 30, Second: 30}},
+		{"1:2.5", "MINUTE_MICROSECOND", Interval{Minute: 1, Second: 2, Microsecond: 500000}},
+		{"1:2.000001", "MINUTE_MICROSECOND", Interval{Minute: 1, Second: 2, Microsecond: 1}},
+		{"3", "QUARTER", Interval{Month: 9}},
+		{"2", "WEEK", Interval{Day: 14}},
+		{"-5", "SECOND", Interval{Second: -5}},
+		{"3000000", "HOUR", Interval{Hour: 3000000}},
+		{"9223372036854775807", "HOUR", Interval{Hour: math.MaxInt64}},
+		{"1 3000000:0", "DAY_MINUTE", Interval{Day: 1, Hour: 3000000}},
+		{"1.2000000", "SECOND_MICROSECOND", Interval{Second: 3}},
+	}
+
+	for i, t := range tbl {
+		iv, err := ParseInterval(t.value, t.unit)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(iv, Equals, t.expect, Commentf("%d failed.", i))
+	}
+
+	errTbl := []struct {
+		value string
+		unit  string
+	}{
+		{"2", "YEAR_MONTH"},
+		{"2-3-4", "YEAR_MONTH"},
+		{"1 2:3", "DAY_SECOND"},
+		{"1 a:3:4", "DAY_SECOND"},
+		{"1:30:00", "MINUTE_SECOND"},
+		{"1:2", "MINUTE_MICROSECOND"},
+		{"1", "UNKNOWN"},
+		{"1:-30", "MINUTE_SECOND"},
+		{"1:+30", "MINUTE_SECOND"},
+		{"--1:30", "MINUTE_SECOND"},
+		{"1-+2", "YEAR_MONTH"},
+		{"+5", "SECOND"},
+		{"1  2:3:4", "DAY_SECOND"},
+		{"9223372036854775808", "HOUR"},
+		{"3074457345618258603", "QUARTER"},
+		{"9223372036854775807:60", "HOUR_MINUTE"},
+	}
+	for i, t := range errTbl {
+		_, err := ParseInterval(t.value, t.unit)
+		c.Assert(err, NotNil, Commentf("%d failed.", i))
+	}
+
+	base := newMysqlTime(2016, 1, 31, 23, 59, 59, 0)
+	applyTbl := []struct {
+		value  string
+		unit   string
+		expect mysqlTime
+	}{
+		{"0-1", "YEAR_MONTH", newMysqlTime(2016, 2, 29, 23, 59, 59, 0)},
+		{"1-1", "YEAR_MONTH", newMysqlTime(2017, 2, 28, 23, 59, 59, 0)},
+		{"1 0:0:1", "DAY_SECOND", newMysqlTime(2016, 2, 2, 0, 0, 0, 0)},
+		{"-1:30", "MINUTE_SECOND", newMysqlTime(2016, 1, 31, 23, 58, 29, 0)},
+		{"0:0.5", "MINUTE_MICROSECOND", newMysqlTime(2016, 1, 31, 23, 59, 59, 500000)},
+	}
+	for i, t := range applyTbl {
+		iv, err := ParseInterval(t.value, t.unit)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		v, err := base.AddIntervalFields(iv)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(v, Equals, t.expect, Commentf("%d failed.", i))
+	}
+
+	// Intervals out of the date range must not overflow into a valid result.
+	overflowTbl := []Interval{
+		{Year: 1 << 62},
+		{Year: -1 << 62},
+		{Month: math.MaxInt64},
+		{Year: 1, Month: math.MaxInt64},
+		{Day: math.MaxInt64, Second: secondsIn24Hour},
+		{Hour: math.MaxInt64},
+		{Minute: math.MinInt64},
+		{Second: math.MaxInt64, Microsecond: 1e6},
+		{Microsecond: math.MaxInt64},
+		{Hour: 3000000000},
+	}
+	for i, iv := range overflowTbl {
+		_, err := base.AddIntervalFields(iv)
+		c.Assert(terror.ErrorEqual(err, ErrDatetimeOutOfRange), IsTrue, Commentf("%d failed.", i))
+	}
+	iv, err := ParseInterval("3000000", "HOUR")
+	c.Assert(err, IsNil)
+	v, err := base.AddIntervalFields(iv)
+	c.Assert(err, IsNil)
+	c.Assert(v, Equals, newMysqlTime(2358, 4, 28, 23, 59, 59, 0))
+}
+
+func (s *testTimeSuite) TestParseDurationDigits(c *C) {
+	defer testleak.AfterTest(c)()
+	tbl := []struct {
+		input  string
+		expect string
+	}{
+		{"1", "00:00:01"},
+		{"12", "00:00:12"},
+		{"123", "00:01:23"},
+		{"1234", "00:12:34"},
+		{"12345", "01:23:45"},
+		{"123456", "12:34:56"},
+		{"000000", "00:00:00"},
+		{"-1234", "-00:12:34"},
+		{"1234.5", "00:12:35"},
+		{"-123456.4", "-12:34:56"},
+	}
+
+	for i, t := range tbl {
+		d, err := ParseDuration(t.input, MinFsp)
+		c.Assert(err, IsNil, Commentf("%d failed.", i))
+		c.Assert(d.String(), Equals, t.expect, Commentf("%d failed.", i))
+	}
+
+	errTbl := []string{
+		"60",
+		"6000",
+		"126000",
+		"12a4",
+		"+1234",
+		"1234567",
+	}
+	for i, t := range errTbl {
+		_, err := ParseDuration(t, MinFsp)
+		c.Assert(err, NotNil, Commentf("%d failed.", i))
+	}
+}